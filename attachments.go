@@ -0,0 +1,270 @@
+package main
+
+// Imports
+import (
+	"io"
+	"os"
+	"bytes"
+	"time"
+	"errors"
+	"strconv"
+	"sync"
+	"net/http"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Types
+type (
+	attachment struct {
+		ID string `bson:"_id"`
+		Name string
+		ContentType string
+		Size int64
+	}
+
+	upload struct {
+		attachment
+		Data []byte
+	}
+
+	pendingUpload struct {
+		Files []*discordgo.MessageAttachment
+		Expires time.Time
+	}
+)
+
+// Variables
+var (
+	errStorageFull = errors.New("attachment storage is full")
+	pendingUploads = map[string]*pendingUpload {}
+	pendingUploadsLock sync.Mutex
+	pendingUploadTimeout = time.Minute * 15
+	downloadClient = &http.Client { Timeout: time.Second * 30 }
+)
+
+// Attachment Functions
+func storageLimit() int64 {
+	limit, err := strconv.ParseInt(os.Getenv("AttachmentLimitMB"), 10, 64)
+
+	if err != nil || limit <= 0 {
+		limit = 25
+	}
+
+	return limit * 1024 * 1024
+}
+
+func resolvedAttachments(interaction *discordgo.InteractionCreate) []*discordgo.MessageAttachment {
+	data := interaction.ApplicationCommandData()
+	files := []*discordgo.MessageAttachment {}
+
+	for _, option := range data.Options {
+		if option.Type == discordgo.ApplicationCommandOptionAttachment && data.Resolved != nil {
+			if file, valid := data.Resolved.Attachments[option.Value.(string)]; valid {
+				files = append(files, file)
+			}
+		}
+	}
+
+	return files
+}
+
+// downloadAttachments copies files off Discord's CDN, whose links expire.
+func downloadAttachments(files []*discordgo.MessageAttachment) ([]*upload, error) {
+	uploads := []*upload {}
+
+	for _, file := range files {
+		if int64(file.Size) > storageLimit() {
+			return nil, errStorageFull
+		}
+
+		response, err := downloadClient.Get(file.URL)
+
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := io.ReadAll(io.LimitReader(response.Body, storageLimit() + 1))
+		response.Body.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if int64(len(data)) > storageLimit() {
+			return nil, errStorageFull
+		}
+
+		uploads = append(uploads, &upload {
+			attachment: attachment {
+				ID: newID(),
+				Name: file.Filename,
+				ContentType: file.ContentType,
+				Size: int64(len(data)),
+			},
+			Data: data,
+		})
+	}
+
+	return uploads, nil
+}
+
+func copyAttachments(message *email) ([]*upload, error) {
+	uploads := []*upload {}
+
+	for _, file := range message.Attachments {
+		data, err := accounts.AttachmentData(file.ID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if data != nil {
+			copied := file
+			copied.ID = newID()
+			uploads = append(uploads, &upload { attachment: copied, Data: data })
+		}
+	}
+
+	return uploads, nil
+}
+
+// startPendingUploads holds the files given to /email while its form is
+// open. The timer only clears its own files, not ones from a later /email.
+func startPendingUploads(userID string, files []*discordgo.MessageAttachment) {
+	pending := &pendingUpload {
+		Files: files,
+		Expires: time.Now().Add(pendingUploadTimeout),
+	}
+
+	pendingUploadsLock.Lock()
+	pendingUploads[userID] = pending
+	pendingUploadsLock.Unlock()
+
+	time.AfterFunc(pendingUploadTimeout, func() {
+		pendingUploadsLock.Lock()
+		defer pendingUploadsLock.Unlock()
+
+		if pendingUploads[userID] == pending {
+			delete(pendingUploads, userID)
+		}
+	})
+}
+
+func takePendingUploads(userID string) []*discordgo.MessageAttachment {
+	pendingUploadsLock.Lock()
+	defer pendingUploadsLock.Unlock()
+
+	pending, valid := pendingUploads[userID]
+	delete(pendingUploads, userID)
+
+	if !valid || time.Now().After(pending.Expires) {
+		return nil
+	}
+
+	return pending.Files
+}
+
+// storeUploads reserves room under the sender's quota, then saves the files
+// and adds them to the email.
+func storeUploads(sender *account, message *email, uploads []*upload) error {
+	size := int64(0)
+
+	for _, file := range uploads {
+		size += file.Size
+	}
+
+	if size > 0 {
+		reserved, err := accounts.ReserveStorage(sender.Username, size, storageLimit())
+
+		if err != nil {
+			return err
+		}
+
+		if !reserved {
+			return errStorageFull
+		}
+	}
+
+	for _, file := range uploads {
+		if err := accounts.SaveAttachment(file.ID, file.Data); err != nil {
+			accounts.AddStorageUsed(sender.Username, -size)
+
+			return err
+		}
+
+		message.Attachments = append(message.Attachments, file.attachment)
+	}
+
+	return nil
+}
+
+// sendWithAttachments stores the files before delivering, and gives the
+// space back if nobody received the email.
+func sendWithAttachments(sender *account, message *email, uploads []*upload) (int, error) {
+	if err := storeUploads(sender, message, uploads); err != nil {
+		return 0, err
+	}
+
+	sent, err := deliverEmail(sender, message)
+
+	if sent <= 0 {
+		if discardErr := discardAttachments(sender.Username, message.Attachments); err == nil {
+			err = discardErr
+		}
+	}
+
+	return sent, err
+}
+
+func discardAttachments(owner string, files []attachment) error {
+	size := int64(0)
+
+	for _, file := range files {
+		if err := accounts.DeleteAttachment(file.ID); err != nil {
+			return err
+		}
+
+		size += file.Size
+	}
+
+	if size <= 0 {
+		return nil
+	}
+
+	return accounts.AddStorageUsed(owner, -size)
+}
+
+func attachmentFiles(message *email, limit int) ([]*discordgo.File, error) {
+	files := []*discordgo.File {}
+
+	for _, file := range message.Attachments {
+		if len(files) >= limit {
+			break
+		}
+
+		data, err := accounts.AttachmentData(file.ID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if data != nil {
+			files = append(files, &discordgo.File {
+				Name: file.Name,
+				ContentType: file.ContentType,
+				Reader: bytes.NewReader(data),
+			})
+		}
+	}
+
+	return files, nil
+}
+
+func formatStorage(size int64) string {
+	if size < 1024 * 1024 {
+		return strconv.FormatFloat(float64(size) / 1024, 'f', 1, 64) + " KB"
+	}
+
+	return strconv.FormatFloat(float64(size) / 1024 / 1024, 'f', 1, 64) + " MB"
+}
@@ -0,0 +1,242 @@
+package main
+
+// Imports
+import (
+	"math"
+	"sync"
+	"strings"
+	"unicode"
+)
+
+// Types
+type (
+	// searchIndex maps every term to the emails holding it, so a search only
+	// looks at the emails a term appears in.
+	searchIndex struct {
+		lock sync.RWMutex
+		postings map[string]map[string]int
+		terms map[string][]string
+		lengths map[string]int
+	}
+)
+
+// Variables
+var (
+	emailIndex = newSearchIndex()
+
+	// The usual BM25 tuning, with title terms counted more than once so they
+	// outrank the same terms in the content.
+	bm25K1 = 1.2
+	bm25B = 0.75
+	titleWeight = 2
+)
+
+// Index Functions
+func newSearchIndex() *searchIndex {
+	return &searchIndex {
+		postings: map[string]map[string]int {},
+		terms: map[string][]string {},
+		lengths: map[string]int {},
+	}
+}
+
+func isIdeograph(char rune) bool {
+	return unicode.In(char, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+// tokenize lowercases text and splits it into words in any script. Scripts
+// written without spaces have no words to split on, so each of their
+// characters is a term of its own.
+func tokenize(text string) []string {
+	tokens := []string {}
+	word := []rune {}
+
+	flush := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, stem(string(word)))
+			word = []rune {}
+		}
+	}
+
+	for _, char := range strings.ToLower(text) {
+		switch {
+		case isIdeograph(char):
+			flush()
+			tokens = append(tokens, string(char))
+		case unicode.IsLetter(char) || unicode.IsNumber(char) || unicode.Is(unicode.Mn, char):
+			word = append(word, char)
+		default:
+			flush()
+		}
+	}
+
+	flush()
+
+	return tokens
+}
+
+// stem strips common English endings so "release", "released" and
+// "releasing" are the same term. Words in other scripts are left alone.
+func stem(word string) string {
+	for _, char := range word {
+		if char < 'a' || char > 'z' {
+			return word
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(word, "sses"):
+		word = strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		word = strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "ing") && len(word) > 5:
+		word = strings.TrimSuffix(word, "ing")
+	case strings.HasSuffix(word, "ed") && len(word) > 4:
+		word = strings.TrimSuffix(word, "ed")
+	case strings.HasSuffix(word, "ly") && len(word) > 4:
+		word = strings.TrimSuffix(word, "ly")
+	case strings.HasSuffix(word, "s") && len(word) > 3 && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		word = strings.TrimSuffix(word, "s")
+	}
+
+	// "planned" and "plan" should meet, but not "fall" and "fal".
+	if last := len(word) - 1; last > 2 && word[last] == word[last - 1] && !strings.ContainsRune("aeiouslz", rune(word[last])) {
+		word = word[:last]
+	}
+
+	if len(word) > 4 && strings.HasSuffix(word, "e") {
+		word = strings.TrimSuffix(word, "e")
+	}
+
+	return word
+}
+
+func (index *searchIndex) removeLocked(id string) {
+	if _, valid := index.lengths[id]; !valid {
+		return
+	}
+
+	for _, term := range index.terms[id] {
+		delete(index.postings[term], id)
+
+		if len(index.postings[term]) <= 0 {
+			delete(index.postings, term)
+		}
+	}
+
+	delete(index.terms, id)
+	delete(index.lengths, id)
+}
+
+// addLocked indexes an email's title and content, replacing what was indexed
+// for it before since drafts can be edited under the same ID.
+func (index *searchIndex) addLocked(message *email) {
+	index.removeLocked(message.ID)
+
+	counts := map[string]int {}
+	length := 0
+
+	for _, term := range tokenize(message.Title) {
+		counts[term] += titleWeight
+		length += titleWeight
+	}
+
+	for _, term := range tokenize(message.Content) {
+		counts[term]++
+		length++
+	}
+
+	for term, count := range counts {
+		if index.postings[term] == nil {
+			index.postings[term] = map[string]int {}
+		}
+
+		index.postings[term][message.ID] = count
+		index.terms[message.ID] = append(index.terms[message.ID], term)
+	}
+
+	index.lengths[message.ID] = length
+}
+
+func (index *searchIndex) add(message *email) {
+	index.lock.Lock()
+	defer index.lock.Unlock()
+
+	index.addLocked(message)
+}
+
+func (index *searchIndex) remove(id string) {
+	index.lock.Lock()
+	defer index.lock.Unlock()
+
+	index.removeLocked(id)
+}
+
+// ensure indexes any of the emails that aren't yet, like the ones saved
+// before the bot started.
+func (index *searchIndex) ensure(messages []*email) {
+	index.lock.Lock()
+	defer index.lock.Unlock()
+
+	for _, message := range messages {
+		if _, valid := index.lengths[message.ID]; !valid {
+			index.addLocked(message)
+		}
+	}
+}
+
+// scores ranks the given emails against the query's terms with BM25, leaving
+// out any email that doesn't hold at least one of them. How common a term is
+// and how long emails are is only counted over those emails, so mail the
+// searcher can't see doesn't change their ranking.
+func (index *searchIndex) scores(query string, ids []string) map[string]float64 {
+	index.lock.RLock()
+	defer index.lock.RUnlock()
+
+	scores := map[string]float64 {}
+	lengths := map[string]int {}
+	totalLength := 0
+
+	for _, id := range ids {
+		if length, valid := index.lengths[id]; valid {
+			lengths[id] = length
+			totalLength += length
+		}
+	}
+
+	documents := float64(len(lengths))
+
+	if documents <= 0 {
+		return scores
+	}
+
+	averageLength := float64(totalLength) / documents
+	seen := map[string]bool {}
+
+	for _, term := range tokenize(query) {
+		if seen[term] {
+			continue
+		}
+
+		seen[term] = true
+		postings := index.postings[term]
+		emails := map[string]int {}
+
+		for id := range lengths {
+			if count, valid := postings[id]; valid {
+				emails[id] = count
+			}
+		}
+
+		frequency := float64(len(emails))
+		idf := math.Log(1 + (documents - frequency + 0.5) / (frequency + 0.5))
+
+		for id, count := range emails {
+			tf := float64(count)
+			norm := 1 - bm25B + bm25B * float64(lengths[id]) / math.Max(averageLength, 1)
+			scores[id] += idf * tf * (bm25K1 + 1) / (tf + bm25K1 * norm)
+		}
+	}
+
+	return scores
+}
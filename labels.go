@@ -0,0 +1,105 @@
+package main
+
+// Imports
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Variables
+var (
+	labelMaxLength = 32
+)
+
+// Label Functions
+func hasLabel(entry *mailEntry, label string) bool {
+	for _, applied := range entry.Labels {
+		if applied == label {
+			return true
+		}
+	}
+
+	return false
+}
+
+func withLabel(entries []*mailEntry, label string) []*mailEntry {
+	labelled := []*mailEntry {}
+
+	for _, entry := range entries {
+		if hasLabel(entry, label) {
+			labelled = append(labelled, entry)
+		}
+	}
+
+	return labelled
+}
+
+// cleanseLabel lowercases a label name and makes sure it only holds letters,
+// numbers, dashes and underscores, since it's also used as a database key.
+func cleanseLabel(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	if name == "" || len([]rune(name)) > labelMaxLength {
+		return name, false
+	}
+
+	for _, char := range name {
+		if !unicode.IsLetter(char) && !unicode.IsNumber(char) && char != '-' && char != '_' {
+			return name, false
+		}
+	}
+
+	return name, true
+}
+
+func setEntryLabel(entry *mailEntry, label string, added bool) error {
+	labels := []string {}
+
+	for _, applied := range entry.Labels {
+		if applied != label {
+			labels = append(labels, applied)
+		}
+	}
+
+	if added {
+		labels = append(labels, label)
+		sort.Strings(labels)
+	}
+
+	entry.Labels = labels
+
+	return accounts.SaveEntry(entry)
+}
+
+// deleteLabel takes a label off every email it was applied to before
+// removing it from the account.
+func deleteLabel(owner string, label string) error {
+	for _, mailbox := range []string {inboxMailbox, sentMailbox, trashMailbox} {
+		found, err := accounts.Entries(owner, mailbox)
+
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range withLabel(found, label) {
+			if err = setEntryLabel(entry, label, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	return accounts.SetLabel(owner, label, false)
+}
+
+func sortedLabels(user *account) []string {
+	labels := []string {}
+
+	for label := range user.Labels {
+		labels = append(labels, label)
+	}
+
+	sort.Strings(labels)
+
+	return labels
+}
@@ -0,0 +1,570 @@
+package main
+
+// Imports
+import (
+	"os"
+	"sort"
+	"time"
+	"errors"
+	"strconv"
+	"strings"
+	"crypto/rand"
+)
+
+// Variables
+var (
+	errRecallExpired = errors.New("the recall window has passed")
+	priorities = []string {"low", "normal", "high"}
+
+	// Email IDs are typed back by users, so leave out look-alike characters.
+	emailIDAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+	emailIDLength = 6
+)
+
+// Mail Functions
+func newEmailID() (string, error) {
+	return uniqueEmailID(func(id string) (bool, error) {
+		existing, err := accounts.EmailByID(id)
+
+		return existing != nil, err
+	})
+}
+
+// uniqueEmailID picks an ID that exists reports as free, so the stores can
+// make IDs while migrating, before they're in use.
+func uniqueEmailID(exists func(id string) (bool, error)) (string, error) {
+	id := make([]byte, emailIDLength)
+
+	for {
+		rand.Read(id)
+
+		for index := range id {
+			id[index] = emailIDAlphabet[int(id[index]) % len(emailIDAlphabet)]
+		}
+
+		taken, err := exists(string(id))
+
+		if err != nil || !taken {
+			return string(id), err
+		}
+	}
+}
+
+func splitUsernames(value string) []string {
+	usernames := []string {}
+
+	for _, username := range strings.Split(value, ",") {
+		if username = strings.TrimPrefix(strings.TrimSpace(username), "@"); username != "" {
+			usernames = append(usernames, username)
+		}
+	}
+
+	return usernames
+}
+
+func allRecipients(message *email) []string {
+	recipients := []string {}
+	seen := map[string]bool {}
+
+	for _, list := range [][]string {message.Recipients, message.CC, message.BCC} {
+		for _, username := range list {
+			if !seen[username] {
+				seen[username] = true
+				recipients = append(recipients, username)
+			}
+		}
+	}
+
+	return recipients
+}
+
+// visibleBCC only shows blind copies to the author, outside of their inbox.
+func visibleBCC(entry *mailEntry) []string {
+	if entry.Owner != entry.Email.Author || entry.Mailbox == inboxMailbox {
+		return []string {}
+	}
+
+	return entry.Email.BCC
+}
+
+func emailText(entry *mailEntry) string {
+	message := entry.Email
+	lines := []string {
+		"ID: #" + message.ID,
+		"Title: \"" + message.Title + "\"",
+		"Author: @" + message.Author,
+		"Date: " + message.Date,
+	}
+
+	for index, list := range [][]string {message.Recipients, message.CC, visibleBCC(entry)} {
+		usernames := []string {}
+
+		for _, username := range list {
+			usernames = append(usernames, "@" + username)
+		}
+
+		if index == 0 || len(usernames) > 0 {
+			lines = append(lines, []string {"Recipients", "CC", "BCC"}[index] + ": " + strings.Join(usernames, ", "))
+		}
+	}
+
+	if len(message.Attachments) > 0 {
+		names := []string {}
+
+		for _, file := range message.Attachments {
+			names = append(names, file.Name)
+		}
+
+		lines = append(lines, "Attachments: " + strings.Join(names, ", "))
+	}
+
+	return strings.Join(lines, "\n") + "\nContent:\n\n" + message.Content
+}
+
+func cleanseEmailID(id string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(id), "#"))
+}
+
+// deliverEmail sends to every recipient that accepts mail from the sender
+// and returns how many did.
+func deliverEmail(sender *account, message *email) (int, error) {
+	accepted := []string {}
+
+	for _, username := range allRecipients(message) {
+		userData, err := accounts.AccountByUsername(username)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if userData != nil {
+			isAContact := userData.ContactList[sender.Username]
+			isBlocked := userData.BlockList[sender.Username]
+			blockedThem := sender.BlockList[username]
+
+			if !(userData.ProtectInbox && !isAContact) && !isBlocked && !blockedThem {
+				accepted = append(accepted, username)
+			}
+		}
+	}
+
+	if len(accepted) <= 0 {
+		return 0, nil
+	}
+
+	message.Author = sender.Username
+	message.Sent = time.Now()
+	message.Date = createDate(message.Sent)
+
+	if err := sendEmail(message, accepted); err != nil {
+		return 0, err
+	}
+
+	return len(accepted), nil
+}
+
+func saveDraft(owner string, draft *email) (*email, error) {
+	id, err := newEmailID()
+
+	if err != nil {
+		return nil, err
+	}
+
+	draft.ID = id
+	draft.Author = owner
+	draft.Sent = time.Now()
+
+	if err = accounts.SaveEmail(draft); err != nil {
+		return nil, err
+	}
+
+	return draft, saveEntry(owner, draftsMailbox, draft)
+}
+
+func sendEmail(message *email, recipients []string) error {
+	if message.ID == "" {
+		id, err := newEmailID()
+
+		if err != nil {
+			return err
+		}
+
+		message.ID = id
+	}
+
+	if message.ThreadID == "" {
+		message.ThreadID = message.ID
+	}
+
+	if err := accounts.SaveEmail(message); err != nil {
+		return err
+	}
+
+	emailIndex.add(message)
+
+	for _, recipient := range recipients {
+		if err := saveEntry(recipient, inboxMailbox, message); err != nil {
+			return err
+		}
+	}
+
+	return saveEntry(message.Author, sentMailbox, message)
+}
+
+func saveEntry(owner string, mailbox string, message *email) error {
+	return accounts.SaveEntry(&mailEntry {
+		ID: newID(),
+		Owner: owner,
+		Mailbox: mailbox,
+		EmailID: message.ID,
+		Date: message.Sent,
+		Read: mailbox == sentMailbox,
+		Email: message,
+	})
+}
+
+func ownEntries(owner string, mailbox string, emailID string) ([]*mailEntry, error) {
+	owned := []*mailEntry {}
+	found, err := accounts.EntriesByEmail(cleanseEmailID(emailID))
+
+	for _, entry := range found {
+		if entry.Owner == owner && entry.Mailbox == mailbox {
+			owned = append(owned, entry)
+		}
+	}
+
+	return owned, err
+}
+
+// findEntry looks an email up in the owner's inbox, then their sent box.
+func findEntry(owner string, emailID string) (*mailEntry, error) {
+	for _, mailbox := range []string {inboxMailbox, sentMailbox} {
+		owned, err := ownEntries(owner, mailbox, emailID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(owned) > 0 {
+			return owned[0], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func openEntry(entry *mailEntry) error {
+	if entry.Read {
+		return nil
+	}
+
+	entry.Read = true
+
+	return accounts.SetRead(entry.Owner, []string {entry.ID}, true)
+}
+
+func countUnread(entries []*mailEntry) int {
+	unread := 0
+
+	for _, entry := range entries {
+		if !entry.Read {
+			unread++
+		}
+	}
+
+	return unread
+}
+
+func threadOf(message *email) string {
+	if message.ThreadID == "" {
+		return message.ID
+	}
+
+	return message.ThreadID
+}
+
+// threadEntries lists one entry per email the owner holds in a thread.
+func threadEntries(owner string, threadID string) ([]*mailEntry, error) {
+	thread := []*mailEntry {}
+	seen := map[string]bool {}
+
+	for _, mailbox := range []string {inboxMailbox, sentMailbox} {
+		found, err := accounts.Entries(owner, mailbox)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range found {
+			if threadOf(entry.Email) == threadID && !seen[entry.EmailID] {
+				seen[entry.EmailID] = true
+				thread = append(thread, entry)
+			}
+		}
+	}
+
+	sort.Slice(thread, func(i, j int) bool {
+		return thread[i].Date.Before(thread[j].Date)
+	})
+
+	return thread, nil
+}
+
+// latestInThreads keeps the newest entry of every thread, oldest thread first.
+func latestInThreads(entries []*mailEntry) []*mailEntry {
+	latest := []*mailEntry {}
+	seen := map[string]bool {}
+
+	for index := len(entries) - 1; index >= 0; index-- {
+		if thread := threadOf(entries[index].Email); !seen[thread] {
+			seen[thread] = true
+			latest = append([]*mailEntry {entries[index]}, latest...)
+		}
+	}
+
+	return latest
+}
+
+func threadSizes(entries []*mailEntry) map[string]int {
+	sizes := map[string]int {}
+	seen := map[string]bool {}
+
+	for _, entry := range entries {
+		if !seen[entry.EmailID] {
+			seen[entry.EmailID] = true
+			sizes[threadOf(entry.Email)]++
+		}
+	}
+
+	return sizes
+}
+
+func cleansePriority(value string) string {
+	for _, priority := range priorities {
+		if value == priority {
+			return value
+		}
+	}
+
+	return "normal"
+}
+
+// priorityRank orders priorities from low to high, with emails from before
+// priorities existed counting as normal.
+func priorityRank(message *email) int {
+	switch message.Priority {
+	case "low":
+		return 0
+	case "high":
+		return 2
+	}
+
+	return 1
+}
+
+func toggleStar(entry *mailEntry) error {
+	entry.Starred = !entry.Starred
+
+	return accounts.SetStarred(entry.Owner, []string {entry.ID}, entry.Starred)
+}
+
+// threadImportance ranks every thread by its most important email, where a
+// star outranks any priority.
+func threadImportance(entries []*mailEntry) map[string]int {
+	importance := map[string]int {}
+
+	for _, entry := range entries {
+		rank := priorityRank(entry.Email)
+		thread := threadOf(entry.Email)
+
+		if entry.Starred {
+			rank += len(priorities)
+		}
+
+		if current, valid := importance[thread]; !valid || rank > current {
+			importance[thread] = rank
+		}
+	}
+
+	return importance
+}
+
+func replyEmail(original *email, sender string, all bool, title string, content string) *email {
+	recipients := []string {}
+	cc := []string {}
+	seen := map[string]bool {sender: true}
+
+	for _, username := range append([]string {original.Author}, original.Recipients...) {
+		if !seen[username] && (all || len(recipients) <= 0) {
+			seen[username] = true
+			recipients = append(recipients, username)
+		}
+	}
+
+	// Copies stay copies on reply-all, and blind copies are never replied to.
+	for _, username := range original.CC {
+		if !seen[username] && all {
+			seen[username] = true
+			cc = append(cc, username)
+		}
+	}
+
+	if title == "" {
+		title = original.Title
+	}
+
+	if !strings.HasPrefix(title, "Re: ") {
+		title = "Re: " + title
+	}
+
+	return &email {
+		Title: shortenTitle(title, emailTitleLength),
+		Recipients: recipients,
+		CC: cc,
+		Content: content,
+		ParentID: original.ID,
+		ThreadID: threadOf(original),
+	}
+}
+
+func forwardedEmail(original *email, recipients []string, note string) *email {
+	content := strings.Join([]string {
+		"---------- Forwarded email ----------",
+		"From: @" + original.Author,
+		"Date: " + original.Date,
+		"Title: " + original.Title,
+		"",
+		original.Content,
+	}, "\n")
+
+	if strings.TrimSpace(note) != "" {
+		content = note + "\n\n" + content
+	}
+
+	title := original.Title
+
+	if !strings.HasPrefix(title, "Fwd: ") {
+		title = "Fwd: " + title
+	}
+
+	return &email {
+		Title: shortenTitle(title, emailTitleLength),
+		Recipients: recipients,
+		Content: content,
+	}
+}
+
+func deleteEntries(owner string, entries []*mailEntry) error {
+	ids := []string {}
+
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+
+	if len(ids) <= 0 {
+		return nil
+	}
+
+	if err := accounts.DeleteEntries(owner, ids); err != nil {
+		return err
+	}
+
+	checked := map[string]bool {}
+
+	// An email is only removed once no mailbox holds it anymore.
+	for _, entry := range entries {
+		if checked[entry.EmailID] {
+			continue
+		}
+
+		checked[entry.EmailID] = true
+		remaining, err := accounts.EntriesByEmail(entry.EmailID)
+
+		if err == nil && len(remaining) <= 0 {
+			err = accounts.DeleteEmail(entry.EmailID)
+			emailIndex.remove(entry.EmailID)
+		}
+
+		if err == nil && len(remaining) <= 0 {
+			err = discardAttachments(entry.Email.Author, entry.Email.Attachments)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func recallWindow() time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv("RecallSeconds"))
+
+	if err != nil || seconds < 0 {
+		seconds = 60
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// recallEmail takes a sent email back out of every inbox that hasn't opened
+// it, trashed unopened copies included, and returns how many were recalled
+// and how many had already read it. When nobody saw it, the author gets it
+// back as a draft.
+func recallEmail(sent *mailEntry) (int, int, error) {
+	if time.Since(sent.Email.Sent) > recallWindow() {
+		return 0, 0, errRecallExpired
+	}
+
+	found, err := accounts.EntriesByEmail(sent.EmailID)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	recalled := 0
+	kept := 0
+
+	for _, entry := range found {
+		inboxed := entry.Mailbox == inboxMailbox || (entry.Mailbox == trashMailbox && entry.TrashedFrom == inboxMailbox)
+
+		if !inboxed {
+			continue
+		}
+
+		if entry.Read {
+			kept++
+
+			continue
+		}
+
+		if err = accounts.DeleteEntries(entry.Owner, []string {entry.ID}); err != nil {
+			return recalled, kept, err
+		}
+
+		recalled++
+	}
+
+	if kept <= 0 {
+		sent.Mailbox = draftsMailbox
+		err = accounts.SaveEntry(sent)
+	}
+
+	return recalled, kept, err
+}
+
+func parseDate(date string) time.Time {
+	fields := strings.Fields(date)
+
+	if len(fields) == 3 {
+		fields[1] = strings.TrimRight(fields[1], "stndrh,") + ","
+	}
+
+	parsed, err := time.Parse("January 2, 2006", strings.Join(fields, " "))
+
+	if err != nil {
+		return time.Time {}
+	}
+
+	return parsed
+}
@@ -1,1669 +1,4995 @@
-package main
-
-// Imports
-import (
-	"fmt"
-	"os"
-	"time"
-	"math"
-	"syscall"
-	"context"
-	"strconv"
-	"errors"
-	"strings"
-	"runtime"
-	"bytes"
-	"os/signal"
-
-	"github.com/bwmarrin/discordgo"
-	"github.com/joho/godotenv"
-
-	"go.mongodb.org/mongo-driver/mongo"
-    "go.mongodb.org/mongo-driver/bson" 
-    "go.mongodb.org/mongo-driver/mongo/options"
-)
-
-// Types
-type (
-	customCommand struct {
-		Group string
-		Description string
-		Options []*discordgo.ApplicationCommandOption
-		Usage string
-		Run func(bot *discordgo.Session, interaction *discordgo.InteractionCreate)
-	}
-
-	email struct {
-		Author string
-		Title string
-		Recipients []string
-		Content string
-		Date string
-	}
-
-	twoFactor struct {
-		Active bool
-		Question string
-		Answer string
-	}
-)
-
-// Variables
-var (
-	embedColor = 0x2f3136
-	database *mongo.Collection
-	cooldowns = map[string]bool {}
-	guildCount = 0
-	userCount = 0
-)
-
-// Main Function
-func main() {
-	godotenv.Load()
-
-	client, err := mongo.Connect(
-		context.TODO(),
-		options.Client().ApplyURI(os.Getenv("MongoURI")),
-	)
-
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	bot, err := discordgo.New(os.Getenv("BotToken"))
-
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	bot.Identify.Intents = discordgo.IntentsGuilds |
-		discordgo.IntentsGuildMessages |
-		discordgo.IntentsGuildMessageReactions |
-		discordgo.IntentsGuildMembers
-
-	database = client.Database("DiscordBots").Collection("EtsukoAccounts")
-
-	bot.AddHandler(ready)
-	bot.AddHandler(interactionCreate)
-	bot.AddHandler(guildCreate)
-	bot.AddHandler(guildDelete)
-	bot.AddHandler(guildMemberAdd)
-	bot.AddHandler(guildMemberRemove)
-
-	err = bot.Open()
-
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	exit := make(chan os.Signal, 1)
-	signal.Notify(
-		exit, 
-		syscall.SIGINT, 
-		syscall.SIGTERM, 
-		os.Interrupt, 
-		os.Kill,
-	)
-
-	<-exit
-	bot.Close()
-}
-
-// Event Functions
-func ready(bot *discordgo.Session, ready *discordgo.Ready) {
-	commands := []*discordgo.ApplicationCommand {}
-
-	for name, cmd := range listAppCommands() {
-		commands = append(commands, &discordgo.ApplicationCommand {
-			Name: name,
-			Type: discordgo.ChatApplicationCommand,
-			Description: cmd.Description,
-			Options: cmd.Options,
-		})
-	}
-
-	bot.ApplicationCommandBulkOverwrite(bot.State.User.ID, "", commands)
-	bot.UpdateStreamingStatus(1, "/commands", "https://twitch.tv/etsukobot")
-	fmt.Printf("Managing emails within %v guilds!", len(ready.Guilds))
-}
-
-func guildCreate(bot *discordgo.Session, guild *discordgo.GuildCreate) {
-	guildCount++
-	userCount += guild.MemberCount
-}
-
-func guildDelete(bot *discordgo.Session, guild *discordgo.GuildDelete) {
-	guildCount--
-	userCount -= guild.MemberCount
-}
-
-func guildMemberAdd(bot *discordgo.Session, guild *discordgo.GuildMemberAdd) {
-	userCount++
-}
-
-func guildMemberRemove(bot *discordgo.Session, guild *discordgo.GuildMemberRemove) {
-	userCount--
-}
-
-func interactionCreate(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-	if interaction.Type == discordgo.InteractionApplicationCommand && interaction.GuildID != "" {
-		name := interaction.ApplicationCommandData().Name
-		cmd, valid := listAppCommands()[name]
-		data, err := findFromMongo(bson.M {"UserID": interaction.Member.User.ID})
-
-		if err == nil && valid {
-			if _, isOnCooldown := cooldowns[interaction.Member.User.ID]; isOnCooldown {
-				bot.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse {
-					Type: discordgo.InteractionResponseChannelMessageWithSource,
-					Data: &discordgo.InteractionResponseData {
-						Flags: 1 << 6,
-						Content: "You're on cooldown for `3s`!",
-					},
-				})
-
-				return
-			}
-
-			if _, dataValid := data["UserID"]; !(!dataValid && !(map[string]bool {"signup": true, "login": true})[name]) {
-				cmd.Run(bot, interaction)
-
-				cooldowns[interaction.Member.User.ID] = true
-
-				time.AfterFunc(time.Second * 3, func() {
-					delete(cooldowns, interaction.Member.User.ID)
-				})
-
-				return
-			}
-
-			bot.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse {
-				Type: discordgo.InteractionResponseChannelMessageWithSource,
-				Data: &discordgo.InteractionResponseData {
-					Flags: 1 << 6,
-					Content: "Run `/signup` or `/login` first!",
-				},
-			})
-		}
-	}
-}
-
-// Mongo Functions
-func findFromMongo(data bson.M) (bson.M, error) {
-	var results bson.M
-
-	err := database.FindOne(context.TODO(), data).Decode(&results)
-
-	if err != nil && err != mongo.ErrNoDocuments {
-		return results, err
-	}
-
-	return results, nil
-}
-
-func updateInMongo(action string, data bson.M, newValue bson.M) error {
-	_, err := database.UpdateOne(context.TODO(), data, bson.M {action: newValue})
-
-	return err
-}
-
-// Utility Functions
-func listAppCommands() map[string]*customCommand {
-	return map[string]*customCommand {
-		"ping": &customCommand {
-			Group: "Fun",
-			Description: "Pong!",
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				start := time.Now().UTC().UnixMilli()
-
-				bot.InteractionRespond(
-					interaction.Interaction,
-					&discordgo.InteractionResponse {
-						Type: discordgo.InteractionResponseChannelMessageWithSource,
-						Data: &discordgo.InteractionResponseData {
-							Flags: 1 << 1,
-							Content: "Pinging...",
-						},
-					},
-				)
-
-				api := time.Now().UTC().UnixMilli() - start
-				start = time.Now().UTC().UnixMilli()
-
-				_, err := findFromMongo(bson.M {"UserID": interaction.Member.User.ID})
-
-				db := time.Now().UTC().UnixMilli() - start
-
-				if err == nil {
-					bot.InteractionResponseEdit(
-						bot.State.User.ID,
-						interaction.Interaction, 
-						&discordgo.WebhookEdit {
-							Content: strings.Join([]string {
-								fmt.Sprintf("API Latency: `%vms`", api),
-								fmt.Sprintf("Database Latency: `%vms`", db),
-							}, "\n"),
-						},
-					)
-				}
-			},
-		},
-		"signup": &customCommand {
-			Group: "Personal",
-			Options: []*discordgo.ApplicationCommandOption {
-				{
-					Type: discordgo.ApplicationCommandOptionString,
-					Name: "username",
-					Description: "The username for the account.",
-					Required: true,
-				},
-				{
-					Type: discordgo.ApplicationCommandOptionString,
-					Name: "password",
-					Description: "The password for the account.",
-					Required: true,
-				},
-			},
-			Description: "Signs you up for my services.",
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {	
-				data, err := findFromMongo(bson.M {"UserID": interaction.Member.User.ID})
-				options := interaction.ApplicationCommandData().Options
-
-				if err == nil {
-					if _, valid := data["UserID"]; valid {
-						bot.InteractionRespond(
-							interaction.Interaction,
-							&discordgo.InteractionResponse {
-								Type: discordgo.InteractionResponseChannelMessageWithSource,
-								Data: &discordgo.InteractionResponseData {
-									Flags: 1 << 6,
-									Content: "You've already signed up!",
-								},
-							},
-						)
-
-						return
-					}
-
-					username := options[0].StringValue()
-					password := options[1].StringValue()
-					data, err = findFromMongo(bson.M {"Username": username})
-
-					if err == nil {
-						if _, valid := data["UserID"]; valid {
-							bot.InteractionRespond(
-								interaction.Interaction,
-								&discordgo.InteractionResponse {
-									Type: discordgo.InteractionResponseChannelMessageWithSource,
-									Data: &discordgo.InteractionResponseData {
-										Flags: 1 << 6,
-										Content: "That username is already under an account!",
-									},
-								},
-							)
-
-							return
-						}
-
-						if len(strings.Split(username, "")) > 25 {
-							bot.InteractionRespond(
-								interaction.Interaction,
-								&discordgo.InteractionResponse {
-									Type: discordgo.InteractionResponseChannelMessageWithSource,
-									Data: &discordgo.InteractionResponseData {
-										Flags: 1 << 6,
-										Content: "The account username cannot be over `25` letters!",
-									},
-								},
-							)
-
-							return
-						}
-
-						if len(strings.Split(password, "")) < 8 || len(strings.Split(password, "")) > 32 {
-							bot.InteractionRespond(
-								interaction.Interaction,
-								&discordgo.InteractionResponse {
-									Type: discordgo.InteractionResponseChannelMessageWithSource,
-									Data: &discordgo.InteractionResponseData {
-										Flags: 1 << 6,
-										Content: "The account password cannot be under `8` letters, or over `32` letters!",
-									},
-								},
-							)
-
-							return
-						}
-
-						_, err := database.InsertOne(context.TODO(), bson.M {
-							"UserID": "",
-							"Username": username,
-							"Password": password,
-							"2FA": twoFactor {
-								Active: false,
-								Question: "",
-								Answer: "",
-							},
-							"SignUpDate": createDate(time.Now()),
-							"SentEmails": []*email {},
-							"InboxedEmails": []*email {},
-							"DraftedEmails": []*email {},
-							"ContactList": map[string]bool {},
-							"BlockList": map[string]bool {},
-							"ProtectInbox": true,
-						})
-
-						if err == nil {
-							bot.InteractionRespond(
-								interaction.Interaction,
-								&discordgo.InteractionResponse {
-									Type: discordgo.InteractionResponseChannelMessageWithSource,
-									Data: &discordgo.InteractionResponseData {
-										Flags: 1 << 6,
-										Content: fmt.Sprintf("You've signed up as `@%v`, congrats! Now, run `/login`.", username),
-									},
-								},
-							)
-						}
-					}
-				}
-			},
-		},
-		"login": &customCommand {
-			Group: "Personal",
-			Options: []*discordgo.ApplicationCommandOption {
-				{
-					Type: discordgo.ApplicationCommandOptionString,
-					Name: "username",
-					Description: "The username for the account.",
-					Required: true,
-				},
-				{
-					Type: discordgo.ApplicationCommandOptionString,
-					Name: "password",
-					Description: "The password for the account.",
-					Required: true,
-				},
-			},
-			Description: "Logs you into an account.",
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				options := interaction.ApplicationCommandData().Options
-				username := options[0].StringValue()
-				password := options[1].StringValue()
-				data, err := findFromMongo(bson.M {"Username": username, "Password": password})
-
-				webhookError(bot, err)
-
-				if err == nil {
-					if _, valid := data["UserID"]; !valid {
-						bot.InteractionRespond(
-							interaction.Interaction,
-							&discordgo.InteractionResponse {
-								Type: discordgo.InteractionResponseChannelMessageWithSource,
-								Data: &discordgo.InteractionResponseData {
-									Flags: 1 << 6,
-									Content: "Those credentials don't match any account!",
-								},
-							},
-						)
-
-						return
-					}
-
-					bot.InteractionRespond(
-						interaction.Interaction,
-						&discordgo.InteractionResponse {
-							Type: discordgo.InteractionResponseChannelMessageWithSource,
-							Data: &discordgo.InteractionResponseData {
-								Flags: 1 << 6,
-								Content: "Logging you out of any previous account...",
-							},
-						},
-					)
-
-					err = updateInMongo(
-						"$set", 
-						bson.M {"UserID": interaction.Member.User.ID}, 
-						bson.M {"UserID": ""},
-					)
-
-					webhookError(bot, err)
-
-					if err == nil {
-						bot.InteractionResponseEdit(
-							bot.State.User.ID,
-							interaction.Interaction,
-							&discordgo.WebhookEdit { Content: "Logging into the account..." },
-						)
-
-						err = updateInMongo(
-							"$set",
-							bson.M {"Username": username, "Password": password},
-							bson.M {"UserID": interaction.Member.User.ID},
-						)
-
-						webhookError(bot, err)
-
-						if err == nil {
-							bot.InteractionResponseEdit(
-								bot.State.User.ID,
-								interaction.Interaction,
-								&discordgo.WebhookEdit { Content: fmt.Sprintf("You are now logged into `@%v`!", username), },
-							)
-						}
-					}
-				}
-			},
-		},
-		"account": &customCommand {
-			Group: "Personal",
-			Description: "Shows info on the account you're using.",
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				data, err := findFromMongo(bson.M {"UserID": interaction.Member.User.ID})
-
-				webhookError(bot, err)
-
-				if err == nil {
-					blocked := 0
-					contacts := 0
-
-					for range data["ContactList"].(bson.M) {
-						contacts++
-					}
-
-					for range data["BlockList"].(bson.M) {
-						blocked++
-					}
-
-					bot.InteractionRespond(
-						interaction.Interaction,
-						&discordgo.InteractionResponse {
-							Type: discordgo.InteractionResponseChannelMessageWithSource,
-							Data: &discordgo.InteractionResponseData {
-								Flags: 1 << 6,
-								Embeds: []*discordgo.MessageEmbed {
-									{
-										Color: embedColor,
-										Description: "This account's info.",
-										Fields: []*discordgo.MessageEmbedField {
-											{
-												Name: "<:list:932178353010659338> Info",
-												Value: strings.Join([]string {
-													fmt.Sprintf("Username: `@%v`", data["Username"].(string)),
-													fmt.Sprintf("Sign Up Date: `%v`", data["SignUpDate"].(string)),
-													fmt.Sprintf("Emails Sent: `%v`", len(data["SentEmails"].(bson.A))),
-													fmt.Sprintf("Inbox Size: `%v`", len(data["InboxedEmails"].(bson.A))),
-													fmt.Sprintf("Contact List Size: `%v`", contacts),
-													fmt.Sprintf("Block List Size: `%v`", blocked),
-													fmt.Sprintf("Password: `%v`", data["Password"].(string)),
-												}, "\n"),
-												Inline: true,
-											},
-										},
-									},
-								},
-							},
-						},
-					)
-				}
-			},
-		},
-		"addcontact": &customCommand {
-			Group: "Personal",
-			Description: "Adds a contact to the list.",
-			Options: []*discordgo.ApplicationCommandOption {
-				{
-					Type: discordgo.ApplicationCommandOptionString,
-					Name: "username",
-					Description: "The username for the contact.",
-					Required: true,
-				},
-			},
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				username := interaction.ApplicationCommandData().Options[0].StringValue()
-				data, err := findFromMongo(bson.M {"UserID": interaction.Member.User.ID})
-
-				webhookError(bot, err)
-
-				if err == nil {
-					userData, err := findFromMongo(bson.M {"Username": username})
-
-					if err == nil {
-						if _, valid := userData["UserID"]; !valid {
-							bot.InteractionRespond(
-								interaction.Interaction,
-								&discordgo.InteractionResponse {
-									Type: discordgo.InteractionResponseChannelMessageWithSource,
-									Data: &discordgo.InteractionResponseData {
-										Flags: 1 << 6,
-										Content: "That username isn't under any account!",
-									},
-								},
-							)
-
-							return
-						}
-
-						if _, blockedThem := (data["BlockList"].(bson.M))[username]; blockedThem {
-							bot.InteractionRespond(
-								interaction.Interaction,
-								&discordgo.InteractionResponse {
-									Type: discordgo.InteractionResponseChannelMessageWithSource,
-									Data: &discordgo.InteractionResponseData {
-										Flags: 1 << 6,
-										Content: "You've blocked that account!",
-									},
-								},
-							)
-
-							return
-						}
-
-						err = updateInMongo(
-							"$set", 
-							bson.M {"UserID": interaction.Member.User.ID}, 
-							bson.M {("ContactList." + username): true},
-						)
-
-						webhookError(bot, err)
-
-						if err == nil {
-							bot.InteractionRespond(
-								interaction.Interaction,
-								&discordgo.InteractionResponse {
-									Type: discordgo.InteractionResponseChannelMessageWithSource,
-									Data: &discordgo.InteractionResponseData {
-										Flags: 1 << 6,
-										Content: fmt.Sprintf("`@%v` has been added to the contact list.", username),
-									},
-								},
-							)
-						}
-					}
-				}
-			},
-		},
-		"delcontact": &customCommand {
-			Group: "Personal",
-			Description: "Deletes a contact from the list.",
-			Options: []*discordgo.ApplicationCommandOption {
-				{
-					Type: discordgo.ApplicationCommandOptionString,
-					Name: "username",
-					Description: "The username for the contact.",
-					Required: true,
-				},
-			},
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				username := interaction.ApplicationCommandData().Options[0].StringValue()
-				data, err := findFromMongo(bson.M {"Username": username})
-
-				webhookError(bot, err)
-
-				if err == nil {
-					if _, valid := data["UserID"]; !valid {
-						bot.InteractionRespond(
-							interaction.Interaction,
-							&discordgo.InteractionResponse {
-								Type: discordgo.InteractionResponseChannelMessageWithSource,
-								Data: &discordgo.InteractionResponseData {
-									Flags: 1 << 6,
-									Content: "That username isn't under any account!",
-								},
-							},
-						)
-
-						return
-					}
-
-					err = updateInMongo(
-						"$unset", 
-						bson.M {"UserID": interaction.Member.User.ID}, 
-						bson.M {("ContactList." + username): true},
-					)
-
-					webhookError(bot, err)
-
-					if err == nil {
-						bot.InteractionRespond(
-							interaction.Interaction,
-							&discordgo.InteractionResponse {
-								Type: discordgo.InteractionResponseChannelMessageWithSource,
-								Data: &discordgo.InteractionResponseData {
-									Flags: 1 << 6,
-									Content: fmt.Sprintf("`@%v` has been deleted from the contact list.", username),
-								},
-							},
-						)
-					}
-				}
-			},
-		},
-		"contacts": &customCommand {
-			Group: "Personal",
-			Description: "Lists the contacts.",
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				data, err := findFromMongo(bson.M {"UserID": interaction.Member.User.ID})
-
-				webhookError(bot, err)
-				
-				if err == nil {
-					contacts := []string {}
-
-					for name := range data["ContactList"].(bson.M) {
-						contacts = append(contacts, fmt.Sprintf("`@%v`", name))
-					}
-
-					if len(contacts) <= 0 {
-						contacts = append(contacts, "`...`")
-					}
-
-					bot.InteractionRespond(
-						interaction.Interaction,
-						&discordgo.InteractionResponse {
-							Type: discordgo.InteractionResponseChannelMessageWithSource,
-							Data: &discordgo.InteractionResponseData {
-								Flags: 1 << 6,
-								Embeds: []*discordgo.MessageEmbed {
-									{
-										Color: embedColor,
-										Description: "Emails from contacts get inboxed normally.",
-										Fields: []*discordgo.MessageEmbedField {
-											{
-												Name: "<:contact:932176590140473344> List",
-												Value: strings.Join(contacts, ", "),
-												Inline: true,
-											},
-										},
-									},
-								},
-							},
-						},
-					)
-				}
-			},
-		},
-		"inbox": &customCommand {
-			Group: "Personal",
-			Description: "Lists your inboxed emails.",
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				data, err := findFromMongo(bson.M {"UserID": interaction.Member.User.ID})
-
-				webhookError(bot, err)
-
-				if err == nil {
-					unknown := []string {}
-					normal := []string {}
-					
-					for _, inboxedEmail := range data["InboxedEmails"].(bson.A) {
-						actualEmail := inboxedEmail.(bson.M)
-						entry := fmt.Sprintf("`@%v`: %v", actualEmail["author"].(string), actualEmail["title"].(string))
-
-						if _, valid := (data["ContactList"].(bson.M))[actualEmail["author"].(string)]; valid {
-							normal = append(normal, entry)
-						} else {
-							unknown = append(unknown, entry)
-						}
-					}
-
-					if len(unknown) <= 0 {
-						unknown = append(unknown, "`...`")
-					}
-
-					if len(normal) <= 0 {
-						normal = append(normal, "`...`")
-					}
-
-					bot.InteractionRespond(
-						interaction.Interaction,
-						&discordgo.InteractionResponse {
-							Type: discordgo.InteractionResponseChannelMessageWithSource,
-							Data: &discordgo.InteractionResponseData {
-								Flags: 1 << 6,
-								Content: "To view any email(s), use the `/search` command.",
-								Embeds: []*discordgo.MessageEmbed {
-									{
-										Color: embedColor,
-										Description: "Emails from contacts are under `Normal`.",
-										Fields: []*discordgo.MessageEmbedField {
-											{
-												Name: "<:letter:932398954526687272> Normal",
-												Value: strings.Join(normal, "\n"),
-												Inline: true,
-											},
-											{
-												Name: "<:warning:932177711307300914> Unknown",
-												Value: strings.Join(unknown, "\n"),
-												Inline: true,
-											},
-										},
-									},
-								},
-							},
-						},
-					)
-				}
-			},
-		},
-		"email": &customCommand {
-			Group: "Personal",
-			Description: "Sends an email.",
-			Options: []*discordgo.ApplicationCommandOption {
-				{
-					Type: discordgo.ApplicationCommandOptionString,
-					Name: "usernames",
-					Description: "The usernames to send to (separate them with commas).",
-					Required: true,
-				},
-				{
-					Type: discordgo.ApplicationCommandOptionString,
-					Name: "title",
-					Description: "The title for the email.",
-					Required: true,
-				},
-				{
-					Type: discordgo.ApplicationCommandOptionString,
-					Name: "content",
-					Description: "The content for the email (the body).",
-					Required: true,
-				},
-			},
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				data, err := findFromMongo(bson.M {"UserID": interaction.Member.User.ID})
-
-				webhookError(bot, err)
-				
-				if err == nil {
-					options := interaction.ApplicationCommandData().Options
-					usernames := strings.Split(options[0].StringValue(), ", ")
-					title := options[1].StringValue()
-					content := options[2].StringValue()
-
-					bot.InteractionRespond(
-						interaction.Interaction,
-						&discordgo.InteractionResponse {
-							Type: discordgo.InteractionResponseChannelMessageWithSource,
-							Data: &discordgo.InteractionResponseData {
-								Flags: 1 << 6,
-								Content: "Sending emails...",
-							},
-						},
-					)
-
-					sent := 0
-
-					for _, username := range usernames {
-						userData, err := findFromMongo(bson.M {"Username": username})
-
-						webhookError(bot, err)
-
-						if _, valid := userData["UserID"]; err == nil && valid {
-							_, isAContact := (userData["ContactList"].(bson.M))[data["Username"].(string)]
-							_, isBlocked := (userData["BlockList"].(bson.M))[data["Username"].(string)]
-							_, blockedThem := (data["BlockList"].(bson.M))[username]
-
-							if !(userData["ProtectInbox"].(bool) && !isAContact) && !isBlocked && !blockedThem {
-								entry := &email {
-									Author: data["Username"].(string),
-									Title: title,
-									Recipients: usernames,
-									Content: strings.ReplaceAll(content, "\\n", "\n"),
-									Date: createDate(time.Now()),
-								}
-
-								err = updateInMongo(
-									"$push",
-									bson.M {"Username": username},
-									bson.M {"InboxedEmails": entry},
-								)
-
-								webhookError(bot, err)
-
-								sent++
-
-								if err == nil {
-									err = updateInMongo(
-										"$push",
-										bson.M {"UserID": interaction.Member.User.ID},
-										bson.M {"SentEmails": entry},
-									)
-
-									webhookError(bot, err)
-								}
-							}
-						}
-					}
-
-					bot.InteractionResponseEdit(
-						bot.State.User.ID,
-						interaction.Interaction,
-						&discordgo.WebhookEdit { Content: fmt.Sprintf("`%v` emails were sent, nice!", sent) },
-					)
-				}
-			},
-		},
-		"search": &customCommand {
-			Group: "Personal",
-			Description: "Shows similar emails from a search.",
-			Options: []*discordgo.ApplicationCommandOption {
-				{
-					Type: discordgo.ApplicationCommandOptionString,
-					Name: "type",
-					Description: "The type of email to search for (inboxed or sent).",
-					Required: true,
-				},
-				{
-					Type: discordgo.ApplicationCommandOptionString,
-					Name: "body",
-					Description: "The title/content to search through any emails for.",
-					Required: true,
-				},
-			},
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				data, err := findFromMongo(bson.M {"UserID": interaction.Member.User.ID})
-
-				webhookError(bot, err)
-
-				if err == nil {
-					bot.InteractionRespond(
-						interaction.Interaction,
-						&discordgo.InteractionResponse {
-							Type: discordgo.InteractionResponseChannelMessageWithSource,
-							Data: &discordgo.InteractionResponseData {
-								Flags: 1 << 6,
-								Content: "Searching through all emails...",
-							},
-						},
-					)
-
-					similar := 0
-					files := []*discordgo.File {}
-					options := interaction.ApplicationCommandData().Options
-					body := options[1].StringValue()
-					emailType := "InboxedEmails"
-
-					if options[0].StringValue() == "sent" {
-						emailType = "SentEmails"
-					}
-
-					for _, inboxedEmail := range data[emailType].(bson.A) {
-						actualEmail := inboxedEmail.(bson.M)
-						
-						if compare(body, actualEmail["title"].(string)) >= 0.4 || compare(body, actualEmail["content"].(string)) >= 0.4 {
-							recipients := []string {}
-
-							for _, recipient := range actualEmail["recipients"].(bson.A) {
-								recipients = append(recipients, "@" + recipient.(string))
-							}
-
-							files = append(files, &discordgo.File {
-								Name: actualEmail["title"].(string) + ".txt",
-								Reader: bytes.NewReader([]byte(fmt.Sprintf(
-									"Title: \"%v\"\nAuthor: @%v\nDate: %v\nRecipients: %v\nContent:\n\n%v",
-									actualEmail["title"].(string),
-									actualEmail["author"].(string),
-									actualEmail["date"].(string),
-									strings.Join(recipients, ", "),
-									actualEmail["content"].(string),
-								))),
-							})
-
-							similar++
-
-							if similar >= 10 {
-								break
-							}
-						}
-					}
-
-					bot.InteractionResponseEdit(
-						bot.State.User.ID,
-						interaction.Interaction,
-						&discordgo.WebhookEdit {
-							Content: fmt.Sprintf("`%v` emails were searched and pulled.", similar),
-							Files: files,
-						},
-					)
-				}
-			},
-		},
-		"commands": &customCommand {
-			Group: "Fun",
-			Description: "Shows the list of commands.",
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				commands := []string {}
-
-				for name := range listAppCommands() {
-					commands = append(commands, fmt.Sprintf("`/%v`", name))
-				}
-
-				bot.InteractionRespond(
-					interaction.Interaction,
-					&discordgo.InteractionResponse {
-						Type: discordgo.InteractionResponseChannelMessageWithSource,
-						Data: &discordgo.InteractionResponseData {
-							Flags: 1 << 1,
-							Embeds: []*discordgo.MessageEmbed {
-								{
-									Color: embedColor,
-									Fields: []*discordgo.MessageEmbedField {
-										{
-											Name: "<:list:932178353010659338> Slash Commands",
-											Value: strings.Join(commands, ", "),
-											Inline: true,
-										},
-									},
-								},
-							},
-						},
-					},
-				)
-			},
-		},
-		"protection": &customCommand {
-			Group: "Personal",
-			Description: "Turns inbox protection on or off.",
-			Options: []*discordgo.ApplicationCommandOption {
-				{
-					Type: discordgo.ApplicationCommandOptionString,
-					Name: "status",
-					Description: "On or off.",
-					Required: true,
-				},
-			},
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				err := errors.New("")
-				status := interaction.ApplicationCommandData().Options[0].StringValue()
-
-				switch status {
-				case "off":
-					err = updateInMongo(
-						"$set",
-						bson.M {"UserID": interaction.Member.User.ID},
-						bson.M {"ProtectInbox": false},
-					)
-				default:
-					err = updateInMongo(
-						"$set",
-						bson.M {"UserID": interaction.Member.User.ID},
-						bson.M {"ProtectInbox": true},
-					)
-				}
-
-				webhookError(bot, err)
-
-				if err == nil {
-					bot.InteractionRespond(
-						interaction.Interaction,
-						&discordgo.InteractionResponse {
-							Type: discordgo.InteractionResponseChannelMessageWithSource,
-							Data: &discordgo.InteractionResponseData {
-								Flags: 1 << 6,
-								Content: fmt.Sprintf("Inbox protection toggled `%v`.", status),
-							},
-						},
-					)
-				}
-			},
-		},
-		"settings": &customCommand {
-			Group: "Personal",
-			Description: "Shows all settings.",
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				data, err := findFromMongo(bson.M {"UserID": interaction.Member.User.ID})
-
-				webhookError(bot, err)
-
-				if err == nil {
-					twoFA := data["2FA"].(bson.M)
-					question := twoFA["question"].(string)
-					answer := twoFA["answer"].(string)
-
-					if question == "" {
-						question = " "
-					}
-
-					if answer == "" {
-						answer = " "
-					}
-
-					bot.InteractionRespond(
-						interaction.Interaction,
-						&discordgo.InteractionResponse {
-							Type: discordgo.InteractionResponseChannelMessageWithSource,
-							Data: &discordgo.InteractionResponseData {
-								Flags: 1 << 6,
-								Embeds: []*discordgo.MessageEmbed {
-									{
-										Color: embedColor,
-										Description: "These are the current account settings.",
-										Fields: []*discordgo.MessageEmbedField {
-											{
-												Name: "<:gear:932392637925822556> Settings",
-												Value: strings.Join([]string {
-													fmt.Sprintf("Inbox Protection: `%v`", data["ProtectInbox"].(bool)),
-													fmt.Sprintf(
-														"2FA: `%v`\n<:blank:932849399598551082>**>** Question: `%v`\n<:blank:932849399598551082>**>** Answer: `%v`",
-														twoFA["active"].(bool),
-														question,
-														answer,
-													),
-												}, "\n"),
-												Inline: true,
-											},
-										},
-									},
-								},
-							},
-						},
-					)
-				}
-			},
-		},
-		"docs": &customCommand {
-			Group: "Fun",
-			Description: "Shows the docs/guide.",
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				bot.InteractionRespond(
-					interaction.Interaction,
-					&discordgo.InteractionResponse {
-						Type: discordgo.InteractionResponseChannelMessageWithSource,
-						Data: &discordgo.InteractionResponseData {
-							Flags: 1 << 1,
-							Embeds: []*discordgo.MessageEmbed {
-								{
-									Color: embedColor,
-									Description: "These are the docs for my services.",
-									Fields: []*discordgo.MessageEmbedField {
-										{
-											Name: "<:contact:932176590140473344> Contacts",
-											Value: "Emails from contacts get sorted under the `Normal` inbox category. Contacts are like friends, and can be removed and added as you please. There might be more potential for contacts in the future, but the only perk that comes with being a contact is being recognized as someone with more access than a normal account.",
-											Inline: true,
-										},
-										{
-											Name: "<:no:932418336229326878> Blocking",
-											Value: "Blocking an account means they can't send you any emails whatsoever, and it will automatically remove them from the contacts list. This actually saves database space, so it is suggested to block those you don't want contacting you through my services.",
-											Inline: true,
-										},
-										{
-											Name: "<:letter:932398954526687272> Emails",
-											Value: "An email contains the recipients, author, date, title, and content. To put new lines in an email, use \"`\\n`\". When searching emails, they are returned in a list of files. The file contains all necessary info, and is titled what the email was titled. This makes it easy to store larger emails and makes them downloadable. When searching through emails, any title or content that is **40%** similar to the search body will be pulled.",
-											Inline: true,
-										},
-										{
-											Name: "<:list:932178353010659338> Slash Commands",
-											Value: "Some command responses will only be visible to the account who runs said command(s). Everything is done through slash commands, and message commands will never be available.",
-											Inline: true,
-										},
-										{
-											Name: "<:gear:932392637925822556> Settings",
-											Value: "`Inbox protection` prevents any emails **NOT** from contacts from sending, and saves database space for those who don't want to be emailed by strangers. `2FA` puts an additional question for the user logging in to get access, making it more secure for the creator of the account. `2FA` has yet to arrive.",
-											Inline: true,
-										},
-										{
-											Name: "<:no:932418336229326878> Errors",
-											Value: "If the bot doesn't respond or stops working, that means an error has appeared. Usually, if the bot continues to work, it is sent to my developers and handled.",
-											Inline: true,
-										},
-									},
-								},
-							},
-						},
-					},
-				)
-			},
-		},
-		"block": &customCommand {
-			Group: "Personal",
-			Description: "Blocks an account.",
-			Options: []*discordgo.ApplicationCommandOption {
-				{
-					Type: discordgo.ApplicationCommandOptionString,
-					Name: "username",
-					Description: "The username to block.",
-					Required: true,
-				},
-			},
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				username := interaction.ApplicationCommandData().Options[0].StringValue()
-				data, err := findFromMongo(bson.M {"Username": username})
-
-				webhookError(bot, err)
-
-				if err == nil {
-					if _, valid := data["UserID"]; !valid {
-						bot.InteractionRespond(
-							interaction.Interaction,
-							&discordgo.InteractionResponse {
-								Type: discordgo.InteractionResponseChannelMessageWithSource,
-								Data: &discordgo.InteractionResponseData {
-									Flags: 1 << 6,
-									Content: "That username isn't under any account!",
-								},
-							},
-						)
-
-						return
-					}
-
-					err = updateInMongo(
-						"$set", 
-						bson.M {"UserID": interaction.Member.User.ID}, 
-						bson.M {("BlockList." + username): true},
-					)
-
-					webhookError(bot, err)
-
-					if err == nil {
-						err = updateInMongo(
-							"$unset", 
-							bson.M {"UserID": interaction.Member.User.ID}, 
-							bson.M {("ContactList." + username): true},
-						)
-
-						webhookError(bot, err)
-
-						if err == nil {
-							bot.InteractionRespond(
-								interaction.Interaction,
-								&discordgo.InteractionResponse {
-									Type: discordgo.InteractionResponseChannelMessageWithSource,
-									Data: &discordgo.InteractionResponseData {
-										Flags: 1 << 6,
-										Content: fmt.Sprintf("`@%v` has been blocked.", username),
-									},
-								},
-							)
-						}
-					}
-				}
-			},
-		},
-		"unblock": &customCommand {
-			Group: "Personal",
-			Description: "Unblocks an account.",
-			Options: []*discordgo.ApplicationCommandOption {
-				{
-					Type: discordgo.ApplicationCommandOptionString,
-					Name: "username",
-					Description: "The username to unblock.",
-					Required: true,
-				},
-			},
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				username := interaction.ApplicationCommandData().Options[0].StringValue()
-				data, err := findFromMongo(bson.M {"Username": username})
-
-				webhookError(bot, err)
-
-				if err == nil {
-					if _, valid := data["UserID"]; !valid {
-						bot.InteractionRespond(
-							interaction.Interaction,
-							&discordgo.InteractionResponse {
-								Type: discordgo.InteractionResponseChannelMessageWithSource,
-								Data: &discordgo.InteractionResponseData {
-									Flags: 1 << 6,
-									Content: "That username isn't under any account!",
-								},
-							},
-						)
-
-						return
-					}
-
-					err = updateInMongo(
-						"$unset", 
-						bson.M {"UserID": interaction.Member.User.ID}, 
-						bson.M {("BlockList." + username): true},
-					)
-
-					webhookError(bot, err)
-
-					if err == nil {
-						bot.InteractionRespond(
-							interaction.Interaction,
-							&discordgo.InteractionResponse {
-								Type: discordgo.InteractionResponseChannelMessageWithSource,
-								Data: &discordgo.InteractionResponseData {
-									Flags: 1 << 6,
-									Content: fmt.Sprintf("`@%v` has been unblocked.", username),
-								},
-							},
-						)
-					}
-				}
-			},
-		},
-		"blocked": &customCommand {
-			Group: "Personal",
-			Description: "Lists the blocked accounts.",
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				data, err := findFromMongo(bson.M {"UserID": interaction.Member.User.ID})
-
-				webhookError(bot, err)
-				
-				if err == nil {
-					blocked := []string {}
-
-					for name := range data["BlockList"].(bson.M) {
-						blocked = append(blocked, fmt.Sprintf("`@%v`", name))
-					}
-
-					if len(blocked) <= 0 {
-						blocked = append(blocked, "`...`")
-					}
-
-					bot.InteractionRespond(
-						interaction.Interaction,
-						&discordgo.InteractionResponse {
-							Type: discordgo.InteractionResponseChannelMessageWithSource,
-							Data: &discordgo.InteractionResponseData {
-								Flags: 1 << 6,
-								Embeds: []*discordgo.MessageEmbed {
-									{
-										Color: embedColor,
-										Description: "Emails from blocked accounts will not be received.",
-										Fields: []*discordgo.MessageEmbedField {
-											{
-												Name: "<:no:932418336229326878> Blocked",
-												Value: strings.Join(blocked, ", "),
-												Inline: true,
-											},
-										},
-									},
-								},
-							},
-						},
-					)
-				}
-			},
-		},
-		"botinfo": &customCommand {
-			Group: "Fun",
-			Description: "Shows info on me.",
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				memory := runtime.MemStats {}
-
-				runtime.ReadMemStats(&memory)
-
-				bar, percent := showProgress(int(memory.Alloc), int(memory.Sys), 15)
-
-				bot.InteractionRespond(
-					interaction.Interaction,
-					&discordgo.InteractionResponse {
-						Type: discordgo.InteractionResponseChannelMessageWithSource,
-						Data: &discordgo.InteractionResponseData {
-							Flags: 1 << 1,
-							Embeds: []*discordgo.MessageEmbed {
-								{
-									Color: embedColor,
-									Fields: []*discordgo.MessageEmbedField {
-										{ 
-											Name: "<:gear:932392637925822556> Info",
-											Value: strings.Join([]string {
-												"Library: [**discordgo**](https://github.com/bwmarrin/discordgo)",
-												fmt.Sprintf("Go Version: `%v`", runtime.Version()),
-												"Support: [**Etsuko Support**](https://discord.gg/n76N8NVksD)",
-												"Invite: [**OAuth2**](https://discord.com/api/oauth2/authorize?client_id=931702913523388436&permissions=139586817088&scope=bot%20applications.commands)",
-												fmt.Sprintf("Guilds: `%v`", guildCount),
-												fmt.Sprintf("Users: `%v`", userCount),
-												fmt.Sprintf("Memory Usage:\n`%v` **%v%v**", bar, percent, "%"),
-											}, "\n"),
-											Inline: true,
-										},
-									},
-								},
-							},
-						},
-					},
-				)
-			},
-		},
-		"delete": &customCommand {
-			Group: "Personal",
-			Description: "Deletes an email of a type.",
-			Options: []*discordgo.ApplicationCommandOption {
-				{
-					Type: discordgo.ApplicationCommandOptionString,
-					Name: "type",
-					Description: "The type of email to delete (inboxed or sent).",
-					Required: true,
-				},
-				{
-					Type: discordgo.ApplicationCommandOptionString,
-					Name: "title",
-					Description: "The title of the email to delete.",
-					Required: true,
-				},
-			},
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				data, err := findFromMongo(bson.M {"UserID": interaction.Member.User.ID})
-				
-				if err == nil {
-					emails := []*email {}
-					options := interaction.ApplicationCommandData().Options	
-					emailType := "InboxedEmails"
-					
-					if options[0].StringValue() == "sen" {
-						emailType = "SentEmails"
-					}
-
-					for _, inboxedEmail := range data[emailType].(bson.A) {
-						actualEmail := inboxedEmail.(bson.M)
-
-						if actualEmail["title"].(string) != options[1].StringValue() {
-							recipients := []string {}
-
-							for _, recipient := range actualEmail["recipients"].(bson.A) {
-								recipients = append(recipients, recipient.(string))
-							}
-
-							emails = append(emails, &email {
-								Author: actualEmail["author"].(string),
-								Title: actualEmail["title"].(string),
-								Recipients: recipients,
-								Content: actualEmail["content"].(string),
-								Date: actualEmail["date"].(string),
-							})
-						}
-					}
-
-					err := updateInMongo(
-						"$set",
-						bson.M {"UserID": interaction.Member.User.ID},
-						bson.M {emailType: emails},
-					)
-
-					webhookError(bot, err)
-
-					if err == nil {
-						bot.InteractionRespond(
-							interaction.Interaction,
-							&discordgo.InteractionResponse {
-								Type: discordgo.InteractionResponseChannelMessageWithSource,
-								Data: &discordgo.InteractionResponseData {
-									Flags: 1 << 6,
-									Content: "The email has been deleted.",
-								},
-							},
-						)
-					}
-				}
-			},
-		},
-		"deleteall": &customCommand {
-			Group: "Personal",
-			Description: "Deletes all emails of a type.",
-			Options: []*discordgo.ApplicationCommandOption {
-				{
-					Type: discordgo.ApplicationCommandOptionString,
-					Name: "type",
-					Description: "The type of emails to delete (inboxed or sent).",
-					Required: true,
-				},
-			},
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				err := errors.New("")
-
-				switch interaction.ApplicationCommandData().Options[0].StringValue() {
-				case "sent":
-					err = updateInMongo(
-						"$set",
-						bson.M {"UserID": interaction.Member.User.ID},
-						bson.M {"SentEmails": []*email {}},
-					)
-				default:
-					err = updateInMongo(
-						"$set",
-						bson.M {"UserID": interaction.Member.User.ID},
-						bson.M {"InboxedEmails": []*email {}},
-					)
-				}
-
-				if err == nil {
-					bot.InteractionRespond(
-						interaction.Interaction,
-						&discordgo.InteractionResponse {
-							Type: discordgo.InteractionResponseChannelMessageWithSource,
-							Data: &discordgo.InteractionResponseData {
-								Flags: 1 << 6,
-								Content: "All emails of that type have been deleted.",
-							},
-						},
-					)
-				}
-			},
-		},
-		"sent": &customCommand {
-			Group: "Personal",
-			Description: "Shows all emails sent on this account.",
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				data, err := findFromMongo(bson.M {"UserID": interaction.Member.User.ID})
-
-				webhookError(bot, err)
-
-				if err == nil {
-					emails := []string {}
-					
-					for _, sentEmail := range data["SentEmails"].(bson.A) {
-						actualEmail := sentEmail.(bson.M)
-						emails = append(emails, fmt.Sprintf("`@%v`: %v", actualEmail["author"].(string), actualEmail["title"].(string)))
-					}
-
-					if len(emails) <= 0 {
-						emails = append(emails, "`...`")
-					}
-
-					bot.InteractionRespond(
-						interaction.Interaction,
-						&discordgo.InteractionResponse {
-							Type: discordgo.InteractionResponseChannelMessageWithSource,
-							Data: &discordgo.InteractionResponseData {
-								Flags: 1 << 6,
-								Content: "To view any email(s), use the `/search` command.",
-								Embeds: []*discordgo.MessageEmbed {
-									{
-										Color: embedColor,
-										Description: "These are all emails sent from this account.",
-										Fields: []*discordgo.MessageEmbedField {
-											{
-												Name: "<:letter:932398954526687272> Emails",
-												Value: strings.Join(emails, "\n"),
-												Inline: true,
-											},
-										},
-									},
-								},
-							},
-						},
-					)
-				}
-			},
-		},
-		"policy": &customCommand {
-			Group: "Fun",
-			Description: "Shows the privacy policy.",
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				bot.InteractionRespond(
-					interaction.Interaction,
-					&discordgo.InteractionResponse {
-						Type: discordgo.InteractionResponseChannelMessageWithSource,
-						Data: &discordgo.InteractionResponseData {
-							Flags: 1 << 1,
-							Embeds: []*discordgo.MessageEmbed {
-								{
-									Color: embedColor,
-									Description: "The privacy policy describes Etsuko's DOs and DON'Ts.",
-									Fields: []*discordgo.MessageEmbedField {
-										{
-											Name: "<:gear:932392637925822556> Policy",
-											Value: "The only data stored by Etsuko is `custom data`, as well as your `user ID`. We do not store your actual Discord account's password, or anything related to it. Etusko acts as its own service when it comes to accounts, meaning your Etsuko account is only related to Etsuko, nothing else. Furthermore, if you wish to have your data removed from the database, you may contact one of the developers of Etsuko.",
-											Inline: true,
-										},
-									},
-								},
-							},
-						},
-					},
-				)
-			},
-		},
-		"terms": &customCommand {
-			Group: "Fun",
-			Description: "Shows the term(s) of service.",
-			Run: func(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
-				bot.InteractionRespond(
-					interaction.Interaction,
-					&discordgo.InteractionResponse {
-						Type: discordgo.InteractionResponseChannelMessageWithSource,
-						Data: &discordgo.InteractionResponseData {
-							Flags: 1 << 1,
-							Embeds: []*discordgo.MessageEmbed {
-								{
-									Color: embedColor,
-									Description: "Your account can be deleted whenever, for any reason. That is the only term.",
-								},
-							},
-						},
-					},
-				)
-			},
-		},
-	}
-}
-
-func formatMonth(month time.Month) string {
-	switch month {
-	case 1: return "January"
-	case 2: return "February"
-	case 3: return "March"
-	case 4: return "April"
-	case 5: return "May"
-	case 6: return "June"
-	case 7: return "July"
-	case 8: return "August"
-	case 9: return "September"
-	case 10: return "October"
-	case 11: return "November"
-	case 12: return "December"
-	}
-
-	return "January"
-}
-
-func formatNumberPos(number int) string {
-	toString := strconv.Itoa(number)
-	split := strings.Split(toString, "") 
-	endNumber, err := strconv.Atoi(split[len(split) - 1])
-
-	if err != nil || endNumber == 1 {
-		return toString + "st"
-	} else if endNumber == 2 {
-		return toString + "nd"
-	} else if endNumber == 3 {
-		return toString + "rd"
-	} else if endNumber > 3 {
-		return toString + "th"
-	}
-
-	return toString + "st"
-}
-
-func createDate(now time.Time) string {
-	return fmt.Sprintf(
-		"%v %v, %v",
-		formatMonth(now.Month()),
-		formatNumberPos(now.Day()),
-		now.Year(),
-	)
-}
-
-func webhookError(bot *discordgo.Session, err error) {
-	if err != nil {
-		bot.WebhookExecute(
-			"",
-			"",
-			false,
-			&discordgo.WebhookParams {
-				Embeds: []*discordgo.MessageEmbed {
-					{
-						Color: embedColor,
-						Description: "An error has appeared!",
-						Fields: []*discordgo.MessageEmbedField {
-							{
-								Name: "<:no:932418336229326878> Error",
-								Value: fmt.Sprintf("`%v`", err),
-								Inline: true,
-							},
-						},
-					},
-				},
-			},
-		)
-	}
-}
-
-func compare(first string, second string) float32 {
-	cleanse(&first)
-	cleanse(&second)
-
-	firstBigrams := map[string]int {}
-
-	for i := 0; i < len(first) - 1; i++ {
-		a := fmt.Sprintf("%c", first[i])
-		b := fmt.Sprintf("%c", first[i + 1])
-
-		bigram := a + b
-
-		var count int
-
-		if value, ok := firstBigrams[bigram]; ok {
-			count = value + 1
-		} else {
-			count = 1
-		}
-
-		firstBigrams[bigram] = count
-	}
-
-	var intersectionSize float32
-	intersectionSize = 0
-
-	for i := 0; i < len(second) - 1; i++ {
-		a := fmt.Sprintf("%c", second[i])
-		b := fmt.Sprintf("%c", second[i + 1])
-
-		bigram := a + b
-
-		var count int
-
-		if value, ok := firstBigrams[bigram]; ok {
-			count = value
-		} else {
-			count = 0
-		}
-
-		if count > 0 {
-			firstBigrams[bigram] = count - 1
-			intersectionSize = intersectionSize + 1
-		}
-	}
-
-	return (2.0 * intersectionSize) / (float32(len(first)) + float32(len(second)) - 2)
-}
-
-func cleanse(body *string) {
-	*body = strings.ReplaceAll(*body, " ", "")
-}
-
-func showProgress(start, max, size int) (string, float64) {
-	percent := float64(start) / float64(max)
-	progress := math.Round(float64(float64(size) * percent))
-	container := float64(size) - progress
-
-	return fmt.Sprintf(
-		"[%v%v]",
-		strings.Repeat("▇", int(progress)),
-		strings.Repeat(" ឵឵", int(container)),
-	), math.Round(float64(percent * 100))
-}
+package main
+
+// Imports
+import (
+	"fmt"
+	"os"
+	"time"
+	"math"
+	"sort"
+	"sync"
+	"syscall"
+	"strconv"
+	"strings"
+	"runtime"
+	"bytes"
+	"os/signal"
+	"crypto/subtle"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/joho/godotenv"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Types
+type (
+	customCommand struct {
+		Group string
+		Description string
+		Options []*discordgo.ApplicationCommandOption
+		Usage string
+		Run func(bot botSession, interaction *discordgo.InteractionCreate)
+	}
+
+	email struct {
+		ID string `bson:"_id"`
+		Author string
+		Title string
+		Recipients []string
+		CC []string
+		BCC []string
+		Content string
+		Attachments []attachment
+		Priority string
+		Date string
+		Sent time.Time
+		ParentID string
+		ThreadID string
+	}
+
+	twoFactor struct {
+		Active bool
+		Question string
+		Answer string
+	}
+
+	// botSession is the part of *discordgo.Session the handlers use, so they
+	// can be driven without a gateway connection.
+	botSession interface {
+		InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse) error
+		InteractionResponseEdit(appID string, interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit) (*discordgo.Message, error)
+		WebhookExecute(webhookID string, token string, wait bool, data *discordgo.WebhookParams) (*discordgo.Message, error)
+		ApplicationCommandBulkOverwrite(appID string, guildID string, commands []*discordgo.ApplicationCommand) ([]*discordgo.ApplicationCommand, error)
+	}
+
+	pendingLogin struct {
+		Username string
+		Expires time.Time
+	}
+)
+
+// Variables
+var (
+	embedColor = 0x2f3136
+	cooldowns = map[string]bool {}
+	pendingLogins = map[string]*pendingLogin {}
+	pendingLoginsLock sync.Mutex
+	pendingLoginTimeout = time.Minute * 5
+	emailTypes = map[string]string {
+		"inboxed": inboxMailbox,
+		"sent": sentMailbox,
+	}
+	listPageSize = 10
+
+	// Usernames are at most 25 characters, so 40 keeps a page of lines under
+	// Discord's 1024 character limit for embed fields.
+	listTitleLength = 40
+
+	// The compose forms hold titles to this, and so does everything else
+	// that sets one.
+	emailTitleLength = 100
+	guildCount = 0
+	userCount = 0
+)
+
+// Main Function
+func main() {
+	godotenv.Load()
+
+	var err error
+
+	switch os.Getenv("Database") {
+	case "sqlite":
+		accounts, err = openSQLite(os.Getenv("SQLitePath"))
+	case "memory":
+		accounts = newMemoryStore()
+	default:
+		accounts, err = connectMongo(os.Getenv("MongoURI"))
+	}
+
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	bot, err := discordgo.New(os.Getenv("BotToken"))
+
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	bot.Identify.Intents = discordgo.IntentsGuilds |
+		discordgo.IntentsGuildMessages |
+		discordgo.IntentsGuildMessageReactions |
+		discordgo.IntentsGuildMembers
+
+	bot.AddHandler(ready)
+	bot.AddHandler(interactionCreate)
+	bot.AddHandler(guildCreate)
+	bot.AddHandler(guildDelete)
+	bot.AddHandler(guildMemberAdd)
+	bot.AddHandler(guildMemberRemove)
+
+	err = bot.Open()
+
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	go runScheduler(bot)
+
+	exit := make(chan os.Signal, 1)
+	signal.Notify(
+		exit, 
+		syscall.SIGINT, 
+		syscall.SIGTERM, 
+		os.Interrupt, 
+		os.Kill,
+	)
+
+	<-exit
+	bot.Close()
+}
+
+// Event Functions
+func ready(bot *discordgo.Session, ready *discordgo.Ready) {
+	registerCommands(bot, bot.State.User.ID)
+	bot.UpdateStreamingStatus(1, "/commands", "https://twitch.tv/etsukobot")
+	fmt.Printf("Managing emails within %v guilds!", len(ready.Guilds))
+}
+
+func guildCreate(bot *discordgo.Session, guild *discordgo.GuildCreate) {
+	guildCount++
+	userCount += guild.MemberCount
+}
+
+func guildDelete(bot *discordgo.Session, guild *discordgo.GuildDelete) {
+	guildCount--
+	userCount -= guild.MemberCount
+}
+
+func guildMemberAdd(bot *discordgo.Session, guild *discordgo.GuildMemberAdd) {
+	userCount++
+}
+
+func guildMemberRemove(bot *discordgo.Session, guild *discordgo.GuildMemberRemove) {
+	userCount--
+}
+
+func interactionCreate(bot *discordgo.Session, interaction *discordgo.InteractionCreate) {
+	handleInteraction(bot, interaction)
+}
+
+// Handler Functions
+func registerCommands(bot botSession, appID string) {
+	commands := []*discordgo.ApplicationCommand {}
+
+	for name, cmd := range listAppCommands() {
+		commands = append(commands, &discordgo.ApplicationCommand {
+			Name: name,
+			Type: discordgo.ChatApplicationCommand,
+			Description: cmd.Description,
+			Options: cmd.Options,
+		})
+	}
+
+	bot.ApplicationCommandBulkOverwrite(appID, "", commands)
+}
+
+func handleInteraction(bot botSession, interaction *discordgo.InteractionCreate) {
+	if interaction.Type == discordgo.InteractionApplicationCommand && interaction.GuildID != "" {
+		name := interaction.ApplicationCommandData().Name
+		cmd, valid := listAppCommands()[name]
+		data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+		if err == nil && valid {
+			if _, isOnCooldown := cooldowns[interaction.Member.User.ID]; isOnCooldown {
+				bot.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse {
+					Type: discordgo.InteractionResponseChannelMessageWithSource,
+					Data: &discordgo.InteractionResponseData {
+						Flags: 1 << 6,
+						Content: "You're on cooldown for `3s`!",
+					},
+				})
+
+				return
+			}
+
+			if data != nil || (map[string]bool {"signup": true, "login": true})[name] {
+				cmd.Run(bot, interaction)
+
+				cooldowns[interaction.Member.User.ID] = true
+
+				time.AfterFunc(time.Second * 3, func() {
+					delete(cooldowns, interaction.Member.User.ID)
+				})
+
+				return
+			}
+
+			bot.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse {
+				Type: discordgo.InteractionResponseChannelMessageWithSource,
+				Data: &discordgo.InteractionResponseData {
+					Flags: 1 << 6,
+					Content: "Run `/signup` or `/login` first!",
+				},
+			})
+		}
+	}
+
+	if interaction.Type == discordgo.InteractionMessageComponent && interaction.GuildID != "" {
+		if run, valid := listComponents()[customIDArgs(interaction.MessageComponentData().CustomID)[0]]; valid {
+			run(bot, interaction)
+		}
+	}
+
+	if interaction.Type == discordgo.InteractionModalSubmit && interaction.GuildID != "" {
+		if run, valid := listModals()[customIDArgs(interaction.ModalSubmitData().CustomID)[0]]; valid {
+			run(bot, interaction)
+		}
+	}
+}
+
+// Utility Functions
+func listAppCommands() map[string]*customCommand {
+	return map[string]*customCommand {
+		"ping": &customCommand {
+			Group: "Fun",
+			Description: "Pong!",
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				start := time.Now().UTC().UnixMilli()
+
+				bot.InteractionRespond(
+					interaction.Interaction,
+					&discordgo.InteractionResponse {
+						Type: discordgo.InteractionResponseChannelMessageWithSource,
+						Data: &discordgo.InteractionResponseData {
+							Flags: 1 << 1,
+							Content: "Pinging...",
+						},
+					},
+				)
+
+				api := time.Now().UTC().UnixMilli() - start
+				start = time.Now().UTC().UnixMilli()
+
+				_, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+				db := time.Now().UTC().UnixMilli() - start
+
+				if err == nil {
+					bot.InteractionResponseEdit(
+						interaction.AppID,
+						interaction.Interaction, 
+						&discordgo.WebhookEdit {
+							Content: strings.Join([]string {
+								fmt.Sprintf("API Latency: `%vms`", api),
+								fmt.Sprintf("Database Latency: `%vms`", db),
+							}, "\n"),
+						},
+					)
+				}
+			},
+		},
+		"signup": &customCommand {
+			Group: "Personal",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "username",
+					Description: "The username for the account.",
+					Required: true,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "password",
+					Description: "The password for the account.",
+					Required: true,
+				},
+			},
+			Description: "Signs you up for my services.",
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {	
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+				options := interaction.ApplicationCommandData().Options
+
+				if err == nil {
+					if data != nil {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: "You've already signed up!",
+								},
+							},
+						)
+
+						return
+					}
+
+					username := options[0].StringValue()
+					password := options[1].StringValue()
+					data, err = accounts.AccountByUsername(username)
+
+					if err == nil {
+						if data != nil {
+							bot.InteractionRespond(
+								interaction.Interaction,
+								&discordgo.InteractionResponse {
+									Type: discordgo.InteractionResponseChannelMessageWithSource,
+									Data: &discordgo.InteractionResponseData {
+										Flags: 1 << 6,
+										Content: "That username is already under an account!",
+									},
+								},
+							)
+
+							return
+						}
+
+						if len(strings.Split(username, "")) > 25 {
+							bot.InteractionRespond(
+								interaction.Interaction,
+								&discordgo.InteractionResponse {
+									Type: discordgo.InteractionResponseChannelMessageWithSource,
+									Data: &discordgo.InteractionResponseData {
+										Flags: 1 << 6,
+										Content: "The account username cannot be over `25` letters!",
+									},
+								},
+							)
+
+							return
+						}
+
+						if len(strings.Split(password, "")) < 8 || len(strings.Split(password, "")) > 32 {
+							bot.InteractionRespond(
+								interaction.Interaction,
+								&discordgo.InteractionResponse {
+									Type: discordgo.InteractionResponseChannelMessageWithSource,
+									Data: &discordgo.InteractionResponseData {
+										Flags: 1 << 6,
+										Content: "The account password cannot be under `8` letters, or over `32` letters!",
+									},
+								},
+							)
+
+							return
+						}
+
+						hash, err := hashPassword(password)
+
+						webhookError(bot, err)
+
+						if err != nil {
+							return
+						}
+
+						err = accounts.CreateAccount(newAccount(username, hash))
+
+						webhookError(bot, err)
+
+						if err == nil {
+							bot.InteractionRespond(
+								interaction.Interaction,
+								&discordgo.InteractionResponse {
+									Type: discordgo.InteractionResponseChannelMessageWithSource,
+									Data: &discordgo.InteractionResponseData {
+										Flags: 1 << 6,
+										Content: fmt.Sprintf("You've signed up as `@%v`, congrats! Now, run `/login`.", username),
+									},
+								},
+							)
+						}
+					}
+				}
+			},
+		},
+		"login": &customCommand {
+			Group: "Personal",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "username",
+					Description: "The username for the account.",
+					Required: true,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "password",
+					Description: "The password for the account.",
+					Required: true,
+				},
+			},
+			Description: "Logs you into an account.",
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				options := interaction.ApplicationCommandData().Options
+				username := options[0].StringValue()
+				password := options[1].StringValue()
+				data, err := accounts.AccountByUsername(username)
+
+				webhookError(bot, err)
+
+				if err == nil {
+					matches, outdated := false, false
+
+					if data != nil {
+						matches, outdated = checkPassword(data.Password, password)
+					}
+
+					if !matches {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: "Those credentials don't match any account!",
+								},
+							},
+						)
+
+						return
+					}
+
+					if outdated {
+						hash, err := hashPassword(password)
+
+						webhookError(bot, err)
+
+						if err == nil {
+							err = accounts.SetPassword(username, hash)
+
+							webhookError(bot, err)
+						}
+					}
+
+					if data.TwoFactor.Active || data.TOTP.Active {
+						components := []discordgo.MessageComponent {}
+						startPendingLogin(interaction.Member.User.ID, username)
+
+						if data.TwoFactor.Active {
+							components = append(components, discordgo.ActionsRow {
+								Components: []discordgo.MessageComponent {
+									discordgo.TextInput {
+										CustomID: "answer",
+										Label: "Security Question",
+										Placeholder: data.TwoFactor.Question,
+										Style: discordgo.TextInputShort,
+										Required: true,
+										MaxLength: 50,
+									},
+								},
+							})
+						}
+
+						if data.TOTP.Active {
+							components = append(components, discordgo.ActionsRow {
+								Components: []discordgo.MessageComponent {
+									discordgo.TextInput {
+										CustomID: "code",
+										Label: "Authenticator Code",
+										Placeholder: "The 6-digit code, or a recovery code.",
+										Style: discordgo.TextInputShort,
+										Required: true,
+										MinLength: 6,
+										MaxLength: 11,
+									},
+								},
+							})
+						}
+
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseModal,
+								Data: &discordgo.InteractionResponseData {
+									CustomID: "login",
+									Title: "Two-Factor Authentication",
+									Components: components,
+								},
+							},
+						)
+
+						return
+					}
+
+					finishLogin(bot, interaction, username)
+				}
+			},
+		},
+		"account": &customCommand {
+			Group: "Personal",
+			Description: "Shows info on the account you're using.",
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+				sent := []*mailEntry {}
+				inboxed := []*mailEntry {}
+
+				if err == nil {
+					sent, err = accounts.Entries(data.Username, sentMailbox)
+				}
+
+				if err == nil {
+					inboxed, err = accounts.Entries(data.Username, inboxMailbox)
+				}
+
+				webhookError(bot, err)
+
+				if err == nil {
+					bot.InteractionRespond(
+						interaction.Interaction,
+						&discordgo.InteractionResponse {
+							Type: discordgo.InteractionResponseChannelMessageWithSource,
+							Data: &discordgo.InteractionResponseData {
+								Flags: 1 << 6,
+								Embeds: []*discordgo.MessageEmbed {
+									{
+										Color: embedColor,
+										Description: "This account's info.",
+										Fields: []*discordgo.MessageEmbedField {
+											{
+												Name: "<:list:932178353010659338> Info",
+												Value: strings.Join([]string {
+													fmt.Sprintf("Username: `@%v`", data.Username),
+													fmt.Sprintf("Sign Up Date: `%v`", data.SignUpDate),
+													fmt.Sprintf("Emails Sent: `%v`", len(sent)),
+													fmt.Sprintf("Inbox Size: `%v`", len(inboxed)),
+													fmt.Sprintf("Unread Emails: `%v`", countUnread(inboxed)),
+													fmt.Sprintf("Storage Used: `%v / %v`", formatStorage(data.StorageUsed), formatStorage(storageLimit())),
+													fmt.Sprintf("Contact List Size: `%v`", len(data.ContactList)),
+													fmt.Sprintf("Block List Size: `%v`", len(data.BlockList)),
+												}, "\n"),
+												Inline: true,
+											},
+										},
+									},
+								},
+							},
+						},
+					)
+				}
+			},
+		},
+		"addcontact": &customCommand {
+			Group: "Personal",
+			Description: "Adds a contact to the list.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "username",
+					Description: "The username for the contact.",
+					Required: true,
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				username := interaction.ApplicationCommandData().Options[0].StringValue()
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+				webhookError(bot, err)
+
+				if err == nil {
+					userData, err := accounts.AccountByUsername(username)
+
+					if err == nil {
+						if userData == nil {
+							bot.InteractionRespond(
+								interaction.Interaction,
+								&discordgo.InteractionResponse {
+									Type: discordgo.InteractionResponseChannelMessageWithSource,
+									Data: &discordgo.InteractionResponseData {
+										Flags: 1 << 6,
+										Content: "That username isn't under any account!",
+									},
+								},
+							)
+
+							return
+						}
+
+						if data.BlockList[username] {
+							bot.InteractionRespond(
+								interaction.Interaction,
+								&discordgo.InteractionResponse {
+									Type: discordgo.InteractionResponseChannelMessageWithSource,
+									Data: &discordgo.InteractionResponseData {
+										Flags: 1 << 6,
+										Content: "You've blocked that account!",
+									},
+								},
+							)
+
+							return
+						}
+
+						err = accounts.SetContact(data.Username, username, true)
+
+						webhookError(bot, err)
+
+						if err == nil {
+							bot.InteractionRespond(
+								interaction.Interaction,
+								&discordgo.InteractionResponse {
+									Type: discordgo.InteractionResponseChannelMessageWithSource,
+									Data: &discordgo.InteractionResponseData {
+										Flags: 1 << 6,
+										Content: fmt.Sprintf("`@%v` has been added to the contact list.", username),
+									},
+								},
+							)
+						}
+					}
+				}
+			},
+		},
+		"delcontact": &customCommand {
+			Group: "Personal",
+			Description: "Deletes a contact from the list.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "username",
+					Description: "The username for the contact.",
+					Required: true,
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				username := interaction.ApplicationCommandData().Options[0].StringValue()
+				user, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+				webhookError(bot, err)
+
+				if err != nil {
+					return
+				}
+
+				data, err := accounts.AccountByUsername(username)
+
+				webhookError(bot, err)
+
+				if err == nil {
+					if data == nil {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: "That username isn't under any account!",
+								},
+							},
+						)
+
+						return
+					}
+
+					err = accounts.SetContact(user.Username, username, false)
+
+					webhookError(bot, err)
+
+					if err == nil {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: fmt.Sprintf("`@%v` has been deleted from the contact list.", username),
+								},
+							},
+						)
+					}
+				}
+			},
+		},
+		"contacts": &customCommand {
+			Group: "Personal",
+			Description: "Lists the contacts.",
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+				webhookError(bot, err)
+				
+				if err == nil {
+					contacts := []string {}
+
+					for name := range data.ContactList {
+						contacts = append(contacts, fmt.Sprintf("`@%v`", name))
+					}
+
+					if len(contacts) <= 0 {
+						contacts = append(contacts, "`...`")
+					}
+
+					bot.InteractionRespond(
+						interaction.Interaction,
+						&discordgo.InteractionResponse {
+							Type: discordgo.InteractionResponseChannelMessageWithSource,
+							Data: &discordgo.InteractionResponseData {
+								Flags: 1 << 6,
+								Embeds: []*discordgo.MessageEmbed {
+									{
+										Color: embedColor,
+										Description: "Emails from contacts get inboxed normally.",
+										Fields: []*discordgo.MessageEmbedField {
+											{
+												Name: "<:contact:932176590140473344> List",
+												Value: strings.Join(contacts, ", "),
+												Inline: true,
+											},
+										},
+									},
+								},
+							},
+						},
+					)
+				}
+			},
+		},
+		"inbox": &customCommand {
+			Group: "Personal",
+			Description: "Lists your inboxed emails.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "label",
+					Description: "Only list emails with this label.",
+					Required: false,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionBoolean,
+					Name: "starred",
+					Description: "Only list starred emails.",
+					Required: false,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "folder",
+					Description: "Only list emails a pinned saved search finds.",
+					Required: false,
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+				options := interaction.ApplicationCommandData().Options
+				label, _ := cleanseLabel(optionValue(options, "label"))
+				folder, _ := cleanseLabel(optionValue(options, "folder"))
+
+				if err == nil && label != "" && !data.Labels[label] {
+					respondPrivately(bot, interaction, "That label doesn't exist, create it with `/label create`.")
+
+					return
+				}
+
+				if err == nil && folder != "" && !data.SavedSearches[folder].Pinned {
+					respondPrivately(bot, interaction, "There is no folder with that name, pin a saved search as one with `/savedsearch pin`.")
+
+					return
+				}
+
+				var view *discordgo.InteractionResponseData
+
+				if err == nil {
+					view, err = inboxView(data, label, optionBool(options, "starred"), folder, 0)
+				}
+
+				webhookError(bot, err)
+
+				if err == nil {
+					bot.InteractionRespond(
+						interaction.Interaction,
+						&discordgo.InteractionResponse {
+							Type: discordgo.InteractionResponseChannelMessageWithSource,
+							Data: view,
+						},
+					)
+				}
+			},
+		},
+		"email": &customCommand {
+			Group: "Personal",
+			Description: "Sends an email.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "usernames",
+					Description: "The usernames to send to (separate them with commas).",
+					Required: false,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "title",
+					Description: "The title for the email.",
+					Required: false,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "content",
+					Description: "The content for the email (the body).",
+					Required: false,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "cc",
+					Description: "The usernames to copy in (separate them with commas).",
+					Required: false,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "bcc",
+					Description: "The usernames to copy in without the others knowing (separate them with commas).",
+					Required: false,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "send_at",
+					Description: "When to send it in your timezone (like 2026-01-31 09:00, or 09:00).",
+					Required: false,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "priority",
+					Description: "How important the email is (normal by default).",
+					Required: false,
+					Choices: priorityChoices(),
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionAttachment,
+					Name: "attachment1",
+					Description: "A file to attach to the email.",
+					Required: false,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionAttachment,
+					Name: "attachment2",
+					Description: "A file to attach to the email.",
+					Required: false,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionAttachment,
+					Name: "attachment3",
+					Description: "A file to attach to the email.",
+					Required: false,
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+				webhookError(bot, err)
+				
+				if err == nil {
+					options := interaction.ApplicationCommandData().Options
+					files := resolvedAttachments(interaction)
+					usernames := splitUsernames(optionValue(options, "usernames"))
+					cc := splitUsernames(optionValue(options, "cc"))
+					bcc := splitUsernames(optionValue(options, "bcc"))
+					title := optionValue(options, "title")
+					content := optionValue(options, "content")
+					priority := cleansePriority(optionValue(options, "priority"))
+					sendAt, valid := parseSendAt(optionValue(options, "send_at"), accountLocation(data), time.Now())
+					scheduledFor := int64(0)
+
+					if !valid {
+						respondPrivately(bot, interaction, "That send time isn't valid, use a future time like `2026-01-31 09:00`.")
+
+						return
+					}
+
+					if utf8.RuneCountInString(title) > emailTitleLength {
+						respondPrivately(bot, interaction, fmt.Sprintf("Email titles cannot be over `%v` letters!", emailTitleLength))
+
+						return
+					}
+
+					if !sendAt.IsZero() {
+						scheduledFor = sendAt.Unix()
+					}
+
+					// The form only carries its own inputs, so the send time and priority ride along in its ID.
+					customID := fmt.Sprintf("compose:%v:%v", scheduledFor, priority)
+
+					// Without a recipient, title and content the email is written in a form instead.
+					if len(usernames) <= 0 || title == "" || content == "" {
+						startPendingUploads(interaction.Member.User.ID, files)
+
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseModal,
+								Data: &discordgo.InteractionResponseData {
+									CustomID: customID,
+									Title: "New Email",
+									Components: emailInputs(&email {
+										Title: title,
+										Recipients: usernames,
+										CC: cc,
+										BCC: bcc,
+										Content: strings.ReplaceAll(content, "\\n", "\n"),
+									}, true),
+								},
+							},
+						)
+
+						return
+					}
+
+					sendAndReport(bot, interaction, data, &email {
+						Title: title,
+						Recipients: usernames,
+						CC: cc,
+						BCC: bcc,
+						Content: strings.ReplaceAll(content, "\\n", "\n"),
+						Priority: priority,
+					}, func() ([]*upload, error) {
+						return downloadAttachments(files)
+					}, sendAt)
+				}
+			},
+		},
+		"read": &customCommand {
+			Group: "Personal",
+			Description: "Opens an email.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "id",
+					Description: "The ID of the email to open.",
+					Required: true,
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+				var entry *mailEntry
+
+				if err == nil {
+					entry, err = findEntry(data.Username, interaction.ApplicationCommandData().Options[0].StringValue())
+				}
+
+				webhookError(bot, err)
+
+				if err == nil {
+					if entry == nil {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: "There is no email with that ID.",
+								},
+							},
+						)
+
+						return
+					}
+
+					err = openEntry(entry)
+
+					webhookError(bot, err)
+
+					view, err := emailView(entry)
+
+					webhookError(bot, err)
+
+					if err == nil {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: view,
+							},
+						)
+					}
+				}
+			},
+		},
+		"markread": &customCommand {
+			Group: "Personal",
+			Description: "Marks inboxed emails as read.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "id",
+					Description: "The ID of the email to mark (leave empty for all of them).",
+					Required: false,
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+				found := []*mailEntry {}
+
+				if err == nil {
+					if options := interaction.ApplicationCommandData().Options; len(options) > 0 {
+						found, err = ownEntries(data.Username, inboxMailbox, options[0].StringValue())
+					} else {
+						found, err = accounts.Entries(data.Username, inboxMailbox)
+					}
+				}
+
+				ids := []string {}
+
+				for _, entry := range found {
+					if !entry.Read {
+						ids = append(ids, entry.ID)
+					}
+				}
+
+				if err == nil {
+					err = accounts.SetRead(data.Username, ids, true)
+				}
+
+				webhookError(bot, err)
+
+				if err == nil {
+					bot.InteractionRespond(
+						interaction.Interaction,
+						&discordgo.InteractionResponse {
+							Type: discordgo.InteractionResponseChannelMessageWithSource,
+							Data: &discordgo.InteractionResponseData {
+								Flags: 1 << 6,
+								Content: fmt.Sprintf("`%v` emails were marked as read.", len(ids)),
+							},
+						},
+					)
+				}
+			},
+		},
+		"reply": &customCommand {
+			Group: "Personal",
+			Description: "Replies to the author of an email.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "id",
+					Description: "The ID of the email to reply to.",
+					Required: true,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "content",
+					Description: "The content for the reply (leave empty to write it in a form).",
+					Required: false,
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				runReply(bot, interaction, false)
+			},
+		},
+		"replyall": &customCommand {
+			Group: "Personal",
+			Description: "Replies to the author and every recipient of an email.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "id",
+					Description: "The ID of the email to reply to.",
+					Required: true,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "content",
+					Description: "The content for the reply (leave empty to write it in a form).",
+					Required: false,
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				runReply(bot, interaction, true)
+			},
+		},
+		"forward": &customCommand {
+			Group: "Personal",
+			Description: "Forwards an email to other accounts.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "id",
+					Description: "The ID of the email to forward.",
+					Required: true,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "usernames",
+					Description: "The usernames to forward to (separate them with commas).",
+					Required: true,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "note",
+					Description: "A note to put above the forwarded email.",
+					Required: false,
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+				options := interaction.ApplicationCommandData().Options
+				var entry *mailEntry
+
+				if err == nil {
+					entry, err = findEntry(data.Username, options[0].StringValue())
+				}
+
+				webhookError(bot, err)
+
+				if err == nil {
+					if entry == nil {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: "There is no email with that ID.",
+								},
+							},
+						)
+
+						return
+					}
+
+					note := ""
+
+					if len(options) > 2 {
+						note = strings.ReplaceAll(options[2].StringValue(), "\\n", "\n")
+					}
+
+					sendAndReport(bot, interaction, data, forwardedEmail(
+						entry.Email,
+						splitUsernames(options[1].StringValue()),
+						note,
+					), func() ([]*upload, error) {
+						return copyAttachments(entry.Email)
+					}, time.Time {})
+				}
+			},
+		},
+		"draft": &customCommand {
+			Group: "Personal",
+			Description: "Manages drafted emails.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "save",
+					Description: "Saves a new draft.",
+					Options: []*discordgo.ApplicationCommandOption {
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "title",
+							Description: "The title for the email.",
+							Required: true,
+						},
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "usernames",
+							Description: "The usernames to send to (separate them with commas).",
+							Required: false,
+						},
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "content",
+							Description: "The content for the email (the body).",
+							Required: false,
+						},
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "cc",
+							Description: "The usernames to copy in (separate them with commas).",
+							Required: false,
+						},
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "bcc",
+							Description: "The usernames to copy in without the others knowing (separate them with commas).",
+							Required: false,
+						},
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "priority",
+							Description: "How important the email is (normal by default).",
+							Required: false,
+							Choices: priorityChoices(),
+						},
+					},
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "list",
+					Description: "Lists your drafts.",
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "edit",
+					Description: "Edits a draft in a form.",
+					Options: []*discordgo.ApplicationCommandOption {
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "id",
+							Description: "The ID of the draft.",
+							Required: true,
+						},
+					},
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "send",
+					Description: "Sends a draft.",
+					Options: []*discordgo.ApplicationCommandOption {
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "id",
+							Description: "The ID of the draft.",
+							Required: true,
+						},
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "send_at",
+							Description: "When to send it in your timezone (like 2026-01-31 09:00, or 09:00).",
+							Required: false,
+						},
+					},
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "delete",
+					Description: "Deletes a draft.",
+					Options: []*discordgo.ApplicationCommandOption {
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "id",
+							Description: "The ID of the draft.",
+							Required: true,
+						},
+					},
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				subCommand := interaction.ApplicationCommandData().Options[0]
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+				webhookError(bot, err)
+
+				if err != nil {
+					return
+				}
+
+				if subCommand.Name == "save" {
+					title := optionValue(subCommand.Options, "title")
+
+					if utf8.RuneCountInString(title) > emailTitleLength {
+						respondPrivately(bot, interaction, fmt.Sprintf("Email titles cannot be over `%v` letters!", emailTitleLength))
+
+						return
+					}
+
+					draft, err := saveDraft(data.Username, &email {
+						Title: title,
+						Recipients: splitUsernames(optionValue(subCommand.Options, "usernames")),
+						CC: splitUsernames(optionValue(subCommand.Options, "cc")),
+						BCC: splitUsernames(optionValue(subCommand.Options, "bcc")),
+						Content: strings.ReplaceAll(optionValue(subCommand.Options, "content"), "\\n", "\n"),
+						Priority: cleansePriority(optionValue(subCommand.Options, "priority")),
+					})
+
+					webhookError(bot, err)
+
+					if err == nil {
+						respondPrivately(bot, interaction, fmt.Sprintf("The draft has been saved as `#%v`.", draft.ID))
+					}
+
+					return
+				}
+
+				if subCommand.Name == "list" {
+					view, err := draftsView(data, 0)
+
+					webhookError(bot, err)
+
+					if err == nil {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: view,
+							},
+						)
+					}
+
+					return
+				}
+
+				drafts, err := ownEntries(data.Username, draftsMailbox, optionValue(subCommand.Options, "id"))
+
+				webhookError(bot, err)
+
+				if err != nil {
+					return
+				}
+
+				if len(drafts) <= 0 {
+					respondPrivately(bot, interaction, "There is no draft with that ID.")
+
+					return
+				}
+
+				switch subCommand.Name {
+				case "edit":
+					bot.InteractionRespond(
+						interaction.Interaction,
+						&discordgo.InteractionResponse {
+							Type: discordgo.InteractionResponseModal,
+							Data: &discordgo.InteractionResponseData {
+								CustomID: "draft:" + drafts[0].EmailID,
+								Title: "Edit Draft",
+								Components: emailInputs(drafts[0].Email, false),
+							},
+						},
+					)
+				case "send":
+					draft := drafts[0].Email
+
+					if len(allRecipients(draft)) <= 0 || strings.TrimSpace(draft.Title) == "" || strings.TrimSpace(draft.Content) == "" {
+						respondPrivately(bot, interaction, "That draft needs a recipient, a title and content before it can be sent. To finish it, use `/draft edit`.")
+
+						return
+					}
+
+					sendAt, valid := parseSendAt(optionValue(subCommand.Options, "send_at"), accountLocation(data), time.Now())
+
+					if !valid {
+						respondPrivately(bot, interaction, "That send time isn't valid, use a future time like `2026-01-31 09:00`.")
+
+						return
+					}
+
+					// The draft already holds its attachments, so it's moved or
+					// delivered as it is.
+					reportSending(bot, interaction, data, draft, sendAt, func() (int, error) {
+						if !sendAt.IsZero() {
+							return 0, scheduleEntry(drafts[0], sendAt)
+						}
+
+						sent, err := deliverEmail(data, draft)
+
+						if err == nil && sent > 0 {
+							err = deleteEntries(data.Username, drafts)
+						}
+
+						return sent, err
+					})
+				case "delete":
+					err = deleteEntries(data.Username, drafts)
+
+					webhookError(bot, err)
+
+					if err == nil {
+						respondPrivately(bot, interaction, "The draft has been deleted.")
+					}
+				}
+			},
+		},
+		"recall": &customCommand {
+			Group: "Personal",
+			Description: "Takes a sent email back from anyone who hasn't opened it.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "id",
+					Description: "The ID of the sent email.",
+					Required: true,
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+				content := ""
+
+				if err == nil {
+					content, err = runRecall(data, interaction.ApplicationCommandData().Options[0].StringValue())
+				}
+
+				webhookError(bot, err)
+
+				if err == nil {
+					respondPrivately(bot, interaction, content)
+				}
+			},
+		},
+		"label": &customCommand {
+			Group: "Personal",
+			Description: "Manages labels for sorting emails.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "create",
+					Description: "Creates a label.",
+					Options: []*discordgo.ApplicationCommandOption {
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "label",
+							Description: "The name for the label (letters, numbers, dashes and underscores).",
+							Required: true,
+						},
+					},
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "delete",
+					Description: "Deletes a label and takes it off every email.",
+					Options: []*discordgo.ApplicationCommandOption {
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "label",
+							Description: "The name of the label.",
+							Required: true,
+						},
+					},
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "list",
+					Description: "Lists your labels.",
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "apply",
+					Description: "Puts a label on an email.",
+					Options: []*discordgo.ApplicationCommandOption {
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "id",
+							Description: "The ID of the email.",
+							Required: true,
+						},
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "label",
+							Description: "The name of the label.",
+							Required: true,
+						},
+					},
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "remove",
+					Description: "Takes a label off an email.",
+					Options: []*discordgo.ApplicationCommandOption {
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "id",
+							Description: "The ID of the email.",
+							Required: true,
+						},
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "label",
+							Description: "The name of the label.",
+							Required: true,
+						},
+					},
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				subCommand := interaction.ApplicationCommandData().Options[0]
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+				webhookError(bot, err)
+
+				if err != nil {
+					return
+				}
+
+				if subCommand.Name == "list" {
+					view, err := labelsView(data, 0)
+
+					webhookError(bot, err)
+
+					if err == nil {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: view,
+							},
+						)
+					}
+
+					return
+				}
+
+				label, valid := cleanseLabel(optionValue(subCommand.Options, "label"))
+
+				if subCommand.Name == "create" {
+					if !valid {
+						respondPrivately(bot, interaction, fmt.Sprintf("Label names can only have letters, numbers, dashes and underscores, up to `%v` of them.", labelMaxLength))
+
+						return
+					}
+
+					err = accounts.SetLabel(data.Username, label, true)
+
+					webhookError(bot, err)
+
+					if err == nil {
+						respondPrivately(bot, interaction, fmt.Sprintf("The label `%v` has been created.", label))
+					}
+
+					return
+				}
+
+				if !data.Labels[label] {
+					respondPrivately(bot, interaction, "That label doesn't exist, create it with `/label create`.")
+
+					return
+				}
+
+				if subCommand.Name == "delete" {
+					err = deleteLabel(data.Username, label)
+
+					webhookError(bot, err)
+
+					if err == nil {
+						respondPrivately(bot, interaction, fmt.Sprintf("The label `%v` has been deleted.", label))
+					}
+
+					return
+				}
+
+				entry, err := findEntry(data.Username, optionValue(subCommand.Options, "id"))
+
+				if err == nil && entry != nil {
+					err = setEntryLabel(entry, label, subCommand.Name == "apply")
+				}
+
+				webhookError(bot, err)
+
+				if err == nil {
+					message := fmt.Sprintf("The label `%v` has been put on the email.", label)
+
+					if entry == nil {
+						message = "There is no email with that ID."
+					} else if subCommand.Name == "remove" {
+						message = fmt.Sprintf("The label `%v` has been taken off the email.", label)
+					}
+
+					respondPrivately(bot, interaction, message)
+				}
+			},
+		},
+		"scheduled": &customCommand {
+			Group: "Personal",
+			Description: "Manages emails waiting to be sent.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "list",
+					Description: "Lists your scheduled emails.",
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "cancel",
+					Description: "Cancels a scheduled email.",
+					Options: []*discordgo.ApplicationCommandOption {
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "id",
+							Description: "The ID of the scheduled email.",
+							Required: true,
+						},
+					},
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				subCommand := interaction.ApplicationCommandData().Options[0]
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+				webhookError(bot, err)
+
+				if err != nil {
+					return
+				}
+
+				if subCommand.Name == "list" {
+					view, err := scheduledView(data, 0)
+
+					webhookError(bot, err)
+
+					if err == nil {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: view,
+							},
+						)
+					}
+
+					return
+				}
+
+				scheduled, err := ownEntries(data.Username, scheduledMailbox, optionValue(subCommand.Options, "id"))
+
+				if err == nil && len(scheduled) > 0 {
+					err = deleteEntries(data.Username, scheduled)
+				}
+
+				webhookError(bot, err)
+
+				if err == nil {
+					message := "The scheduled email has been cancelled."
+
+					if len(scheduled) <= 0 {
+						message = "There is no scheduled email with that ID."
+					}
+
+					respondPrivately(bot, interaction, message)
+				}
+			},
+		},
+		"star": &customCommand {
+			Group: "Personal",
+			Description: "Stars an email, or unstars it if it already is.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "id",
+					Description: "The ID of the email to star.",
+					Required: true,
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+				var entry *mailEntry
+
+				if err == nil {
+					entry, err = findEntry(data.Username, interaction.ApplicationCommandData().Options[0].StringValue())
+				}
+
+				if err == nil && entry != nil {
+					err = toggleStar(entry)
+				}
+
+				webhookError(bot, err)
+
+				if err == nil {
+					message := "The email has been starred."
+
+					if entry == nil {
+						message = "There is no email with that ID."
+					} else if !entry.Starred {
+						message = "The email has been unstarred."
+					}
+
+					respondPrivately(bot, interaction, message)
+				}
+			},
+		},
+		"savedsearch": &customCommand {
+			Group: "Personal",
+			Description: "Manages searches saved to run again, or to pin as folders in /inbox.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "add",
+					Description: "Saves a search, replacing any saved under the same name.",
+					Options: []*discordgo.ApplicationCommandOption {
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "name",
+							Description: "The name for the search (letters, numbers, dashes and underscores).",
+							Required: true,
+						},
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "query",
+							Description: "What to search for, like from:customer is:unread.",
+							Required: true,
+						},
+					},
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "run",
+					Description: "Runs a saved search.",
+					Options: []*discordgo.ApplicationCommandOption {
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "name",
+							Description: "The name of the search.",
+							Required: true,
+						},
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "type",
+							Description: "The type of email to search through (both by default).",
+							Required: false,
+							Choices: emailTypeChoices(),
+						},
+					},
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "list",
+					Description: "Lists your saved searches.",
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "pin",
+					Description: "Pins a saved search as a folder in /inbox, or unpins it if it already is.",
+					Options: []*discordgo.ApplicationCommandOption {
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "name",
+							Description: "The name of the search.",
+							Required: true,
+						},
+					},
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "delete",
+					Description: "Deletes a saved search.",
+					Options: []*discordgo.ApplicationCommandOption {
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "name",
+							Description: "The name of the search.",
+							Required: true,
+						},
+					},
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				subCommand := interaction.ApplicationCommandData().Options[0]
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+				webhookError(bot, err)
+
+				if err != nil {
+					return
+				}
+
+				if subCommand.Name == "list" {
+					view, err := savedSearchesView(data, 0)
+
+					webhookError(bot, err)
+
+					if err == nil {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: view,
+							},
+						)
+					}
+
+					return
+				}
+
+				name, valid := cleanseLabel(optionValue(subCommand.Options, "name"))
+
+				if subCommand.Name == "add" {
+					query := strings.TrimSpace(optionValue(subCommand.Options, "query"))
+
+					if !valid {
+						respondPrivately(bot, interaction, fmt.Sprintf("Saved search names can only have letters, numbers, dashes and underscores, up to `%v` of them.", labelMaxLength))
+
+						return
+					}
+
+					if _, valid = parseSearch(query, accountLocation(data)); !valid || query == "" {
+						respondPrivately(bot, interaction, "That search isn't valid. Dates look like `before:2026-01-31`, and the only other filters are `from:`, `to:`, `subject:`, `has:attachment` and `is:unread`, `is:read` or `is:starred`.")
+
+						return
+					}
+
+					// Replacing a search keeps it pinned if it was.
+					err = accounts.SetSavedSearch(data.Username, name, &savedSearch {
+						Query: query,
+						Pinned: data.SavedSearches[name].Pinned,
+					})
+
+					webhookError(bot, err)
+
+					if err == nil {
+						respondPrivately(bot, interaction, fmt.Sprintf("The search `%v` has been saved.", name))
+					}
+
+					return
+				}
+
+				saved, found := data.SavedSearches[name]
+
+				if !found {
+					respondPrivately(bot, interaction, "There is no saved search with that name, save one with `/savedsearch add`.")
+
+					return
+				}
+
+				switch subCommand.Name {
+				case "run":
+					mailboxes := []string {inboxMailbox, sentMailbox}
+
+					if mailbox, valid := emailTypes[optionValue(subCommand.Options, "type")]; valid {
+						mailboxes = []string {mailbox}
+					}
+
+					pullSearch(bot, interaction, data, mailboxes, saved.Query)
+				case "pin":
+					saved.Pinned = !saved.Pinned
+					err = accounts.SetSavedSearch(data.Username, name, &saved)
+
+					webhookError(bot, err)
+
+					if err == nil {
+						message := fmt.Sprintf("The search `%v` has been pinned, use `/inbox` with its name to open it.", name)
+
+						if !saved.Pinned {
+							message = fmt.Sprintf("The search `%v` has been unpinned.", name)
+						}
+
+						respondPrivately(bot, interaction, message)
+					}
+				case "delete":
+					err = accounts.SetSavedSearch(data.Username, name, nil)
+
+					webhookError(bot, err)
+
+					if err == nil {
+						respondPrivately(bot, interaction, fmt.Sprintf("The search `%v` has been deleted.", name))
+					}
+				}
+			},
+		},
+		"search": &customCommand {
+			Group: "Personal",
+			Description: "Searches your emails, with filters like from:, to:, subject:, before:, after:, has: and is:.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "query",
+					Description: "What to search for, like from:alice subject:\"release\" has:attachment notes.",
+					Required: true,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "type",
+					Description: "The type of email to search through (both by default).",
+					Required: false,
+					Choices: emailTypeChoices(),
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+				options := interaction.ApplicationCommandData().Options
+				mailboxes := []string {inboxMailbox, sentMailbox}
+
+				if mailbox, valid := emailTypes[optionValue(options, "type")]; valid {
+					mailboxes = []string {mailbox}
+				}
+
+				webhookError(bot, err)
+
+				if err == nil {
+					pullSearch(bot, interaction, data, mailboxes, optionValue(options, "query"))
+				}
+			},
+		},
+		"commands": &customCommand {
+			Group: "Fun",
+			Description: "Shows the list of commands.",
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				commands := []string {}
+
+				for name := range listAppCommands() {
+					commands = append(commands, fmt.Sprintf("`/%v`", name))
+				}
+
+				bot.InteractionRespond(
+					interaction.Interaction,
+					&discordgo.InteractionResponse {
+						Type: discordgo.InteractionResponseChannelMessageWithSource,
+						Data: &discordgo.InteractionResponseData {
+							Flags: 1 << 1,
+							Embeds: []*discordgo.MessageEmbed {
+								{
+									Color: embedColor,
+									Fields: []*discordgo.MessageEmbedField {
+										{
+											Name: "<:list:932178353010659338> Slash Commands",
+											Value: strings.Join(commands, ", "),
+											Inline: true,
+										},
+									},
+								},
+							},
+						},
+					},
+				)
+			},
+		},
+		"protection": &customCommand {
+			Group: "Personal",
+			Description: "Turns inbox protection on or off.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "status",
+					Description: "On or off.",
+					Required: true,
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				status := interaction.ApplicationCommandData().Options[0].StringValue()
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+				if err == nil {
+					err = accounts.SetProtectInbox(data.Username, status != "off")
+				}
+
+				webhookError(bot, err)
+
+				if err == nil {
+					bot.InteractionRespond(
+						interaction.Interaction,
+						&discordgo.InteractionResponse {
+							Type: discordgo.InteractionResponseChannelMessageWithSource,
+							Data: &discordgo.InteractionResponseData {
+								Flags: 1 << 6,
+								Content: fmt.Sprintf("Inbox protection toggled `%v`.", status),
+							},
+						},
+					)
+				}
+			},
+		},
+		"timezone": &customCommand {
+			Group: "Personal",
+			Description: "Sets the timezone for scheduled emails.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "name",
+					Description: "The timezone's name (like Europe/Paris or America/New_York).",
+					Required: true,
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+				webhookError(bot, err)
+
+				if err != nil {
+					return
+				}
+
+				location, err := time.LoadLocation(strings.TrimSpace(interaction.ApplicationCommandData().Options[0].StringValue()))
+
+				if err != nil {
+					respondPrivately(bot, interaction, "That timezone doesn't exist, use a name like `Europe/Paris`.")
+
+					return
+				}
+
+				err = accounts.SetTimezone(data.Username, location.String())
+
+				webhookError(bot, err)
+
+				if err == nil {
+					respondPrivately(bot, interaction, fmt.Sprintf("Your timezone is now `%v`.", location))
+				}
+			},
+		},
+		"settings": &customCommand {
+			Group: "Personal",
+			Description: "Shows all settings.",
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+				webhookError(bot, err)
+
+				if err == nil {
+					question := data.TwoFactor.Question
+					answer := " "
+
+					if question == "" {
+						question = " "
+					}
+
+					if data.TwoFactor.Answer != "" {
+						answer = "Hidden"
+					}
+
+					bot.InteractionRespond(
+						interaction.Interaction,
+						&discordgo.InteractionResponse {
+							Type: discordgo.InteractionResponseChannelMessageWithSource,
+							Data: &discordgo.InteractionResponseData {
+								Flags: 1 << 6,
+								Embeds: []*discordgo.MessageEmbed {
+									{
+										Color: embedColor,
+										Description: "These are the current account settings.",
+										Fields: []*discordgo.MessageEmbedField {
+											{
+												Name: "<:gear:932392637925822556> Settings",
+												Value: strings.Join([]string {
+													fmt.Sprintf("Inbox Protection: `%v`", data.ProtectInbox),
+													fmt.Sprintf("Timezone: `%v`", accountLocation(data)),
+													fmt.Sprintf(
+														"2FA: `%v`\n<:blank:932849399598551082>**>** Question: `%v`\n<:blank:932849399598551082>**>** Answer: `%v`",
+														data.TwoFactor.Active,
+														question,
+														answer,
+													),
+													fmt.Sprintf(
+														"TOTP: `%v`\n<:blank:932849399598551082>**>** Recovery Codes Left: `%v`",
+														data.TOTP.Active,
+														len(data.TOTP.RecoveryCodes),
+													),
+												}, "\n"),
+												Inline: true,
+											},
+										},
+									},
+								},
+							},
+						},
+					)
+				}
+			},
+		},
+		"2fa": &customCommand {
+			Group: "Personal",
+			Description: "Manages two-factor authentication.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "question",
+					Description: "Sets the security question asked when logging in.",
+					Options: []*discordgo.ApplicationCommandOption {
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "question",
+							Description: "The question to ask.",
+							Required: true,
+						},
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "answer",
+							Description: "The answer to the question (not case sensitive).",
+							Required: true,
+						},
+					},
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "setup",
+					Description: "Sets up an authenticator app (TOTP) for logging in.",
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "confirm",
+					Description: "Confirms the authenticator app setup with its first code.",
+					Options: []*discordgo.ApplicationCommandOption {
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "code",
+							Description: "The 6-digit code from the authenticator app.",
+							Required: true,
+						},
+					},
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "disable",
+					Description: "Turns off a type of 2FA.",
+					Options: []*discordgo.ApplicationCommandOption {
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "type",
+							Description: "The type of 2FA to turn off (question or totp).",
+							Required: true,
+						},
+					},
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				subCommand := interaction.ApplicationCommandData().Options[0]
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+				webhookError(bot, err)
+
+				if err != nil {
+					return
+				}
+
+				switch subCommand.Name {
+				case "question":
+					question := strings.TrimSpace(subCommand.Options[0].StringValue())
+					answer := cleanseAnswer(subCommand.Options[1].StringValue())
+
+					if question == "" || answer == "" || len(strings.Split(question, "")) > 100 || len(strings.Split(answer, "")) > 50 {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: "The question cannot be empty or over `100` letters, and the answer cannot be empty or over `50` letters!",
+								},
+							},
+						)
+
+						return
+					}
+
+					hash, err := hashPassword(answer)
+
+					webhookError(bot, err)
+
+					if err == nil {
+						err = accounts.SetTwoFactor(data.Username, twoFactor {
+							Active: true,
+							Question: question,
+							Answer: hash,
+						})
+
+						webhookError(bot, err)
+
+						if err == nil {
+							bot.InteractionRespond(
+								interaction.Interaction,
+								&discordgo.InteractionResponse {
+									Type: discordgo.InteractionResponseChannelMessageWithSource,
+									Data: &discordgo.InteractionResponseData {
+										Flags: 1 << 6,
+										Content: "2FA is on, the question will be asked whenever this account is logged into.",
+									},
+								},
+							)
+						}
+					}
+				case "setup":
+					if data.TOTP.Active {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: "An authenticator app is already set up, run `/2fa disable` first!",
+								},
+							},
+						)
+
+						return
+					}
+
+					secret, err := createTOTPSecret()
+
+					webhookError(bot, err)
+
+					if err != nil {
+						return
+					}
+
+					recoveryCodes, err := createRecoveryCodes(10)
+
+					webhookError(bot, err)
+
+					if err != nil {
+						return
+					}
+
+					uri := createTOTPURI(data.Username, secret)
+					image, err := createTOTPQRCode(uri)
+
+					webhookError(bot, err)
+
+					if err != nil {
+						return
+					}
+
+					hashedCodes := []string {}
+
+					for _, recoveryCode := range recoveryCodes {
+						hashedCode, err := hashRecoveryCode(recoveryCode)
+
+						webhookError(bot, err)
+
+						if err != nil {
+							return
+						}
+
+						hashedCodes = append(hashedCodes, hashedCode)
+					}
+
+					err = accounts.SetTOTP(data.Username, totp {
+						Active: false,
+						Secret: secret,
+						RecoveryCodes: hashedCodes,
+					})
+
+					webhookError(bot, err)
+
+					if err == nil {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: "Scan the QR code with an authenticator app, then run `/2fa confirm` with the code it shows.",
+									Embeds: []*discordgo.MessageEmbed {
+										{
+											Color: embedColor,
+											Description: "Save the recovery codes somewhere safe, each one can log in once without the app.",
+											Fields: []*discordgo.MessageEmbedField {
+												{
+													Name: "<:gear:932392637925822556> Setup",
+													Value: strings.Join([]string {
+														fmt.Sprintf("Secret: `%v`", secret),
+														fmt.Sprintf("URI: `%v`", uri),
+													}, "\n"),
+													Inline: true,
+												},
+												{
+													Name: "<:list:932178353010659338> Recovery Codes",
+													Value: fmt.Sprintf("`%v`", strings.Join(recoveryCodes, "`\n`")),
+													Inline: true,
+												},
+											},
+											Image: &discordgo.MessageEmbedImage {
+												URL: "attachment://totp.png",
+											},
+										},
+									},
+									Files: []*discordgo.File {
+										{
+											Name: "totp.png",
+											ContentType: "image/png",
+											Reader: bytes.NewReader(image),
+										},
+									},
+								},
+							},
+						)
+					}
+				case "confirm":
+					if data.TOTP.Secret == "" || data.TOTP.Active {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: "There's no authenticator app setup to confirm, run `/2fa setup` first!",
+								},
+							},
+						)
+
+						return
+					}
+
+					step, valid := checkTOTPCode(data.TOTP.Secret, subCommand.Options[0].StringValue(), data.TOTP.LastStep, time.Now())
+
+					if !valid {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: "That code is incorrect, make sure the app's clock is right!",
+								},
+							},
+						)
+
+						return
+					}
+
+					data.TOTP.Active = true
+					data.TOTP.LastStep = step
+					err = accounts.SetTOTP(data.Username, data.TOTP)
+
+					webhookError(bot, err)
+
+					if err == nil {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: "TOTP is on, a code will be asked for whenever this account is logged into.",
+								},
+							},
+						)
+					}
+				case "disable":
+					twoFAType := subCommand.Options[0].StringValue()
+
+					switch twoFAType {
+					case "question":
+						err = accounts.SetTwoFactor(data.Username, twoFactor {})
+					case "totp":
+						err = accounts.SetTOTP(data.Username, totp { RecoveryCodes: []string {} })
+					default:
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: "The type must be `question` or `totp`!",
+								},
+							},
+						)
+
+						return
+					}
+
+					webhookError(bot, err)
+
+					if err == nil {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: fmt.Sprintf("2FA `%v` is off.", twoFAType),
+								},
+							},
+						)
+					}
+				}
+			},
+		},
+		"docs": &customCommand {
+			Group: "Fun",
+			Description: "Shows the docs/guide.",
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				bot.InteractionRespond(
+					interaction.Interaction,
+					&discordgo.InteractionResponse {
+						Type: discordgo.InteractionResponseChannelMessageWithSource,
+						Data: &discordgo.InteractionResponseData {
+							Flags: 1 << 1,
+							Embeds: []*discordgo.MessageEmbed {
+								{
+									Color: embedColor,
+									Description: "These are the docs for my services.",
+									Fields: []*discordgo.MessageEmbedField {
+										{
+											Name: "<:contact:932176590140473344> Contacts",
+											Value: "Emails from contacts get sorted under the `Normal` inbox category. Contacts are like friends, and can be removed and added as you please. There might be more potential for contacts in the future, but the only perk that comes with being a contact is being recognized as someone with more access than a normal account.",
+											Inline: true,
+										},
+										{
+											Name: "<:no:932418336229326878> Blocking",
+											Value: "Blocking an account means they can't send you any emails whatsoever, and it will automatically remove them from the contacts list. This actually saves database space, so it is suggested to block those you don't want contacting you through my services.",
+											Inline: true,
+										},
+										{
+											Name: "<:letter:932398954526687272> Emails",
+											Value: "An email contains an ID, the recipients, author, date, title, and content. Every email gets a short ID (like `#k3x9pq`) when it is sent, which is shown in `/inbox` and `/sent`. Use `/read` with an ID to open an email, then the buttons under it to move through your mailbox, reply, forward, delete it or mark it unread. Unread emails are shown in **bold** in `/inbox`, and `/markread` marks one or all of them as read. `/reply` and `/replyall` answer an email by ID and keep the answer in the same thread. `/forward` sends a copy of an email to other accounts, with an optional note on top, and follows the same inbox protection and block list rules as `/email`. `/inbox` and `/sent` show ten emails a page, with buttons to turn the page and a menu to open any of them.",
+											Inline: true,
+										},
+										{
+											Name: "<:letter:932398954526687272> Drafts",
+											Value: "Running `/email` on its own opens a form with room for a multi-line body. Filling in all of its options sends the email straight away instead, and \"`\\n`\" puts new lines in the content. `/draft` saves an email to finish later, lets you edit it in a form and sends it when it's ready.",
+											Inline: true,
+										},
+										{
+											Name: "<:contact:932176590140473344> CC, BCC & Files",
+											Value: "Anyone under `CC` is shown to every recipient, while anyone under `BCC` is only shown to you. Up to three files can be attached with `/email`; they count towards your account's storage, which is freed once every copy of the email is deleted.",
+											Inline: true,
+										},
+										{
+											Name: "<:list:932178353010659338> Scheduling",
+											Value: "Giving `/email` or `/draft send` a `send_at` time (like `2026-01-31 09:00`, or just `09:00` for the next one) sends it later, in the timezone set with `/timezone`, and `/scheduled` lists or cancels those emails. For a short while after sending, the `Undo` button or `/recall` takes an email back out of every inbox that hasn't opened it yet; if nobody has, it goes back to your drafts.",
+											Inline: true,
+										},
+										{
+											Name: "<:no:932418336229326878> Trash",
+											Value: "Deleting an email moves it to the trash, where `/trash restore` brings it back and `/trash empty` deletes it for good; anything left there is deleted on its own after a while, and only then is its storage freed. `/deleteall` and `/trash empty` ask before going ahead.",
+											Inline: true,
+										},
+										{
+											Name: "<:list:932178353010659338> Labels & Stars",
+											Value: "Labels made with `/label create` can be put on any email with `/label apply`, and `/inbox` with a label's name only lists the emails that have it. Emails can be sent with a `low` or `high` priority, and `/star` or the `Star` button marks one that matters to you; starred and high priority emails are listed first in `/inbox`, which can also list only starred ones.",
+											Inline: true,
+										},
+										{
+											Name: "<:list:932178353010659338> Searching",
+											Value: "When searching emails, they are returned in a list of files. The file contains all necessary info, and is named after the email's ID. This makes it easy to store larger emails and makes them downloadable. When searching through emails, any email whose title or content shares a word with the search's text will be pulled, best matches first; words are matched in any language, and English ones also match their other forms, like `release` and `released`. Searches can also be narrowed down with filters, like `from:alice to:bob subject:\"release\" before:2026-01-01 after:2025-12-01 has:attachment is:unread`, and look through both inboxed and sent emails unless a type is given. `/savedsearch add` keeps a search under a name so `/savedsearch run` can run it again, and pinning it with `/savedsearch pin` shows it as a folder in `/inbox`, where its name lists only the emails it finds.",
+											Inline: true,
+										},
+										{
+											Name: "<:list:932178353010659338> Slash Commands",
+											Value: "Some command responses will only be visible to the account who runs said command(s). Everything is done through slash commands, and message commands will never be available.",
+											Inline: true,
+										},
+										{
+											Name: "<:gear:932392637925822556> Settings",
+											Value: "`Inbox protection` prevents any emails **NOT** from contacts from sending, and saves database space for those who don't want to be emailed by strangers. `2FA` puts an additional question for the user logging in to get access, making it more secure for the creator of the account. Set it up with `/2fa question`, or use an authenticator app with `/2fa setup`, and turn either off with `/2fa disable`.",
+											Inline: true,
+										},
+										{
+											Name: "<:no:932418336229326878> Errors",
+											Value: "If the bot doesn't respond or stops working, that means an error has appeared. Usually, if the bot continues to work, it is sent to my developers and handled.",
+											Inline: true,
+										},
+									},
+								},
+							},
+						},
+					},
+				)
+			},
+		},
+		"block": &customCommand {
+			Group: "Personal",
+			Description: "Blocks an account.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "username",
+					Description: "The username to block.",
+					Required: true,
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				username := interaction.ApplicationCommandData().Options[0].StringValue()
+				user, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+				webhookError(bot, err)
+
+				if err != nil {
+					return
+				}
+
+				data, err := accounts.AccountByUsername(username)
+
+				webhookError(bot, err)
+
+				if err == nil {
+					if data == nil {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: "That username isn't under any account!",
+								},
+							},
+						)
+
+						return
+					}
+
+					err = accounts.SetBlocked(user.Username, username, true)
+
+					webhookError(bot, err)
+
+					if err == nil {
+						err = accounts.SetContact(user.Username, username, false)
+
+						webhookError(bot, err)
+
+						if err == nil {
+							bot.InteractionRespond(
+								interaction.Interaction,
+								&discordgo.InteractionResponse {
+									Type: discordgo.InteractionResponseChannelMessageWithSource,
+									Data: &discordgo.InteractionResponseData {
+										Flags: 1 << 6,
+										Content: fmt.Sprintf("`@%v` has been blocked.", username),
+									},
+								},
+							)
+						}
+					}
+				}
+			},
+		},
+		"unblock": &customCommand {
+			Group: "Personal",
+			Description: "Unblocks an account.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "username",
+					Description: "The username to unblock.",
+					Required: true,
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				username := interaction.ApplicationCommandData().Options[0].StringValue()
+				user, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+				webhookError(bot, err)
+
+				if err != nil {
+					return
+				}
+
+				data, err := accounts.AccountByUsername(username)
+
+				webhookError(bot, err)
+
+				if err == nil {
+					if data == nil {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: "That username isn't under any account!",
+								},
+							},
+						)
+
+						return
+					}
+
+					err = accounts.SetBlocked(user.Username, username, false)
+
+					webhookError(bot, err)
+
+					if err == nil {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: fmt.Sprintf("`@%v` has been unblocked.", username),
+								},
+							},
+						)
+					}
+				}
+			},
+		},
+		"blocked": &customCommand {
+			Group: "Personal",
+			Description: "Lists the blocked accounts.",
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+				webhookError(bot, err)
+				
+				if err == nil {
+					blocked := []string {}
+
+					for name := range data.BlockList {
+						blocked = append(blocked, fmt.Sprintf("`@%v`", name))
+					}
+
+					if len(blocked) <= 0 {
+						blocked = append(blocked, "`...`")
+					}
+
+					bot.InteractionRespond(
+						interaction.Interaction,
+						&discordgo.InteractionResponse {
+							Type: discordgo.InteractionResponseChannelMessageWithSource,
+							Data: &discordgo.InteractionResponseData {
+								Flags: 1 << 6,
+								Embeds: []*discordgo.MessageEmbed {
+									{
+										Color: embedColor,
+										Description: "Emails from blocked accounts will not be received.",
+										Fields: []*discordgo.MessageEmbedField {
+											{
+												Name: "<:no:932418336229326878> Blocked",
+												Value: strings.Join(blocked, ", "),
+												Inline: true,
+											},
+										},
+									},
+								},
+							},
+						},
+					)
+				}
+			},
+		},
+		"botinfo": &customCommand {
+			Group: "Fun",
+			Description: "Shows info on me.",
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				memory := runtime.MemStats {}
+
+				runtime.ReadMemStats(&memory)
+
+				bar, percent := showProgress(int(memory.Alloc), int(memory.Sys), 15)
+
+				bot.InteractionRespond(
+					interaction.Interaction,
+					&discordgo.InteractionResponse {
+						Type: discordgo.InteractionResponseChannelMessageWithSource,
+						Data: &discordgo.InteractionResponseData {
+							Flags: 1 << 1,
+							Embeds: []*discordgo.MessageEmbed {
+								{
+									Color: embedColor,
+									Fields: []*discordgo.MessageEmbedField {
+										{ 
+											Name: "<:gear:932392637925822556> Info",
+											Value: strings.Join([]string {
+												"Library: [**discordgo**](https://github.com/bwmarrin/discordgo)",
+												fmt.Sprintf("Go Version: `%v`", runtime.Version()),
+												"Support: [**Etsuko Support**](https://discord.gg/n76N8NVksD)",
+												"Invite: [**OAuth2**](https://discord.com/api/oauth2/authorize?client_id=931702913523388436&permissions=139586817088&scope=bot%20applications.commands)",
+												fmt.Sprintf("Guilds: `%v`", guildCount),
+												fmt.Sprintf("Users: `%v`", userCount),
+												fmt.Sprintf("Memory Usage:\n`%v` **%v%v**", bar, percent, "%"),
+											}, "\n"),
+											Inline: true,
+										},
+									},
+								},
+							},
+						},
+					},
+				)
+			},
+		},
+		"delete": &customCommand {
+			Group: "Personal",
+			Description: "Deletes an email of a type.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "type",
+					Description: "The type of email to delete (inboxed or sent).",
+					Required: true,
+					Choices: emailTypeChoices(),
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "id",
+					Description: "The ID of the email to delete.",
+					Required: true,
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+				
+				if err == nil {
+					options := interaction.ApplicationCommandData().Options	
+					emailType, valid := emailTypes[options[0].StringValue()]
+
+					if !valid {
+						respondPrivately(bot, interaction, "That type doesn't exist, use `inboxed` or `sent`.")
+
+						return
+					}
+
+					emails, err := ownEntries(data.Username, emailType, options[1].StringValue())
+
+					if err == nil {
+						err = trashEntries(emails)
+					}
+
+					webhookError(bot, err)
+
+					if err == nil {
+						message := "The email has been moved to the trash. To bring it back, use `/trash restore`."
+
+						if len(emails) <= 0 {
+							message = "There is no email with that ID."
+						}
+
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: &discordgo.InteractionResponseData {
+									Flags: 1 << 6,
+									Content: message,
+								},
+							},
+						)
+					}
+				}
+			},
+		},
+		"deleteall": &customCommand {
+			Group: "Personal",
+			Description: "Deletes all emails of a type.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "type",
+					Description: "The type of emails to delete (inboxed or sent).",
+					Required: true,
+					Choices: emailTypeChoices(),
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+				typeName := interaction.ApplicationCommandData().Options[0].StringValue()
+				emailType, valid := emailTypes[typeName]
+				found := []*mailEntry {}
+
+				if err == nil && !valid {
+					respondPrivately(bot, interaction, "That type doesn't exist, use `inboxed` or `sent`.")
+
+					return
+				}
+
+				if err == nil {
+					found, err = accounts.Entries(data.Username, emailType)
+				}
+
+				webhookError(bot, err)
+
+				if err == nil {
+					bot.InteractionRespond(
+						interaction.Interaction,
+						&discordgo.InteractionResponse {
+							Type: discordgo.InteractionResponseChannelMessageWithSource,
+							Data: &discordgo.InteractionResponseData {
+								Flags: 1 << 6,
+								Content: fmt.Sprintf("This moves all `%v` %v emails to the trash, are you sure?", len(found), typeName),
+								Components: confirmButtons("deleteall:" + emailType, "Delete All"),
+							},
+						},
+					)
+				}
+			},
+		},
+		"trash": &customCommand {
+			Group: "Personal",
+			Description: "Manages deleted emails.",
+			Options: []*discordgo.ApplicationCommandOption {
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "list",
+					Description: "Lists the emails in your trash.",
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "restore",
+					Description: "Moves an email back to where it was deleted from.",
+					Options: []*discordgo.ApplicationCommandOption {
+						{
+							Type: discordgo.ApplicationCommandOptionString,
+							Name: "id",
+							Description: "The ID of the email to restore.",
+							Required: true,
+						},
+					},
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "empty",
+					Description: "Deletes every email in your trash for good.",
+				},
+			},
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				subCommand := interaction.ApplicationCommandData().Options[0]
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+				trashed := []*mailEntry {}
+
+				if err == nil && subCommand.Name == "restore" {
+					trashed, err = ownEntries(data.Username, trashMailbox, optionValue(subCommand.Options, "id"))
+				} else if err == nil && subCommand.Name == "empty" {
+					trashed, err = accounts.Entries(data.Username, trashMailbox)
+				}
+
+				webhookError(bot, err)
+
+				if err != nil {
+					return
+				}
+
+				switch subCommand.Name {
+				case "list":
+					view, err := trashView(data, 0)
+
+					webhookError(bot, err)
+
+					if err == nil {
+						bot.InteractionRespond(
+							interaction.Interaction,
+							&discordgo.InteractionResponse {
+								Type: discordgo.InteractionResponseChannelMessageWithSource,
+								Data: view,
+							},
+						)
+					}
+				case "restore":
+					err = restoreEntries(trashed)
+
+					webhookError(bot, err)
+
+					if err == nil {
+						message := "The email has been restored."
+
+						if len(trashed) <= 0 {
+							message = "There is no email in the trash with that ID."
+						}
+
+						respondPrivately(bot, interaction, message)
+					}
+				case "empty":
+					bot.InteractionRespond(
+						interaction.Interaction,
+						&discordgo.InteractionResponse {
+							Type: discordgo.InteractionResponseChannelMessageWithSource,
+							Data: &discordgo.InteractionResponseData {
+								Flags: 1 << 6,
+								Content: fmt.Sprintf("This deletes all `%v` emails in the trash for good, are you sure?", len(trashed)),
+								Components: confirmButtons("emptytrash", "Empty Trash"),
+							},
+						},
+					)
+				}
+			},
+		},
+		"sent": &customCommand {
+			Group: "Personal",
+			Description: "Shows all emails sent on this account.",
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+				var view *discordgo.InteractionResponseData
+
+				if err == nil {
+					view, err = sentView(data, 0)
+				}
+
+				webhookError(bot, err)
+
+				if err == nil {
+					bot.InteractionRespond(
+						interaction.Interaction,
+						&discordgo.InteractionResponse {
+							Type: discordgo.InteractionResponseChannelMessageWithSource,
+							Data: view,
+						},
+					)
+				}
+			},
+		},
+		"policy": &customCommand {
+			Group: "Fun",
+			Description: "Shows the privacy policy.",
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				bot.InteractionRespond(
+					interaction.Interaction,
+					&discordgo.InteractionResponse {
+						Type: discordgo.InteractionResponseChannelMessageWithSource,
+						Data: &discordgo.InteractionResponseData {
+							Flags: 1 << 1,
+							Embeds: []*discordgo.MessageEmbed {
+								{
+									Color: embedColor,
+									Description: "The privacy policy describes Etsuko's DOs and DON'Ts.",
+									Fields: []*discordgo.MessageEmbedField {
+										{
+											Name: "<:gear:932392637925822556> Policy",
+											Value: "The only data stored by Etsuko is `custom data`, as well as your `user ID`. We do not store your actual Discord account's password, or anything related to it. Etusko acts as its own service when it comes to accounts, meaning your Etsuko account is only related to Etsuko, nothing else. Furthermore, if you wish to have your data removed from the database, you may contact one of the developers of Etsuko.",
+											Inline: true,
+										},
+									},
+								},
+							},
+						},
+					},
+				)
+			},
+		},
+		"terms": &customCommand {
+			Group: "Fun",
+			Description: "Shows the term(s) of service.",
+			Run: func(bot botSession, interaction *discordgo.InteractionCreate) {
+				bot.InteractionRespond(
+					interaction.Interaction,
+					&discordgo.InteractionResponse {
+						Type: discordgo.InteractionResponseChannelMessageWithSource,
+						Data: &discordgo.InteractionResponseData {
+							Flags: 1 << 1,
+							Embeds: []*discordgo.MessageEmbed {
+								{
+									Color: embedColor,
+									Description: "Your account can be deleted whenever, for any reason. That is the only term.",
+								},
+							},
+						},
+					},
+				)
+			},
+		},
+	}
+}
+
+func listComponents() map[string]func(bot botSession, interaction *discordgo.InteractionCreate) {
+	return map[string]func(bot botSession, interaction *discordgo.InteractionCreate) {
+		"read": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.MessageComponentData().CustomID)
+
+			if data == nil || len(args) < 4 {
+				return
+			}
+
+			owned, err := ownEntries(data.Username, args[2], args[3])
+
+			webhookError(bot, err)
+
+			if err == nil {
+				showEntry(bot, interaction, owned)
+			}
+		},
+		"open": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.MessageComponentData().CustomID)
+			values := interaction.MessageComponentData().Values
+
+			if data == nil || len(args) < 2 || len(values) < 1 {
+				return
+			}
+
+			owned, err := ownEntries(data.Username, args[1], values[0])
+
+			webhookError(bot, err)
+
+			if err == nil {
+				showEntry(bot, interaction, owned)
+			}
+		},
+		"inbox": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.MessageComponentData().CustomID)
+
+			if data == nil || len(args) < 4 {
+				return
+			}
+
+			page, _ := strconv.Atoi(args[1])
+			folder := ""
+
+			// Pages from before folders existed have no folder in their ID.
+			if len(args) > 4 {
+				folder = args[4]
+			}
+
+			view, err := inboxView(data, args[2], args[3] == "true", folder, page)
+
+			webhookError(bot, err)
+
+			if err == nil {
+				bot.InteractionRespond(
+					interaction.Interaction,
+					&discordgo.InteractionResponse {
+						Type: discordgo.InteractionResponseUpdateMessage,
+						Data: view,
+					},
+				)
+			}
+		},
+		"sent": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.MessageComponentData().CustomID)
+
+			if data == nil || len(args) < 2 {
+				return
+			}
+
+			page, _ := strconv.Atoi(args[1])
+			view, err := sentView(data, page)
+
+			webhookError(bot, err)
+
+			if err == nil {
+				bot.InteractionRespond(
+					interaction.Interaction,
+					&discordgo.InteractionResponse {
+						Type: discordgo.InteractionResponseUpdateMessage,
+						Data: view,
+					},
+				)
+			}
+		},
+		"trash": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.MessageComponentData().CustomID)
+
+			if data == nil || len(args) < 2 {
+				return
+			}
+
+			page, _ := strconv.Atoi(args[1])
+			view, err := trashView(data, page)
+
+			webhookError(bot, err)
+
+			if err == nil {
+				bot.InteractionRespond(
+					interaction.Interaction,
+					&discordgo.InteractionResponse {
+						Type: discordgo.InteractionResponseUpdateMessage,
+						Data: view,
+					},
+				)
+			}
+		},
+		"drafts": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.MessageComponentData().CustomID)
+
+			if data == nil || len(args) < 2 {
+				return
+			}
+
+			page, _ := strconv.Atoi(args[1])
+			view, err := draftsView(data, page)
+
+			webhookError(bot, err)
+
+			if err == nil {
+				bot.InteractionRespond(
+					interaction.Interaction,
+					&discordgo.InteractionResponse {
+						Type: discordgo.InteractionResponseUpdateMessage,
+						Data: view,
+					},
+				)
+			}
+		},
+		"scheduled": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.MessageComponentData().CustomID)
+
+			if data == nil || len(args) < 2 {
+				return
+			}
+
+			page, _ := strconv.Atoi(args[1])
+			view, err := scheduledView(data, page)
+
+			webhookError(bot, err)
+
+			if err == nil {
+				bot.InteractionRespond(
+					interaction.Interaction,
+					&discordgo.InteractionResponse {
+						Type: discordgo.InteractionResponseUpdateMessage,
+						Data: view,
+					},
+				)
+			}
+		},
+		"labels": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.MessageComponentData().CustomID)
+
+			if data == nil || len(args) < 2 {
+				return
+			}
+
+			page, _ := strconv.Atoi(args[1])
+			view, err := labelsView(data, page)
+
+			webhookError(bot, err)
+
+			if err == nil {
+				bot.InteractionRespond(
+					interaction.Interaction,
+					&discordgo.InteractionResponse {
+						Type: discordgo.InteractionResponseUpdateMessage,
+						Data: view,
+					},
+				)
+			}
+		},
+		"savedsearches": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.MessageComponentData().CustomID)
+
+			if data == nil || len(args) < 2 {
+				return
+			}
+
+			page, _ := strconv.Atoi(args[1])
+			view, err := savedSearchesView(data, page)
+
+			webhookError(bot, err)
+
+			if err == nil {
+				bot.InteractionRespond(
+					interaction.Interaction,
+					&discordgo.InteractionResponse {
+						Type: discordgo.InteractionResponseUpdateMessage,
+						Data: view,
+					},
+				)
+			}
+		},
+		"reply": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.MessageComponentData().CustomID)
+
+			if data == nil || len(args) < 2 {
+				return
+			}
+
+			entry, err := findEntry(data.Username, args[1])
+
+			webhookError(bot, err)
+
+			if err == nil && entry != nil {
+				bot.InteractionRespond(interaction.Interaction, replyModal(entry, len(args) > 2 && args[2] == "all"))
+			}
+		},
+		"forward": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.MessageComponentData().CustomID)
+
+			if data == nil || len(args) < 2 {
+				return
+			}
+
+			entry, err := findEntry(data.Username, args[1])
+
+			webhookError(bot, err)
+
+			if err == nil && entry != nil {
+				bot.InteractionRespond(
+					interaction.Interaction,
+					&discordgo.InteractionResponse {
+						Type: discordgo.InteractionResponseModal,
+						Data: &discordgo.InteractionResponseData {
+							CustomID: "forward:" + entry.EmailID,
+							Title: "Forward Email",
+							Components: []discordgo.MessageComponent {
+								discordgo.ActionsRow {
+									Components: []discordgo.MessageComponent {
+										discordgo.TextInput {
+											CustomID: "usernames",
+											Label: "Usernames",
+											Placeholder: "The usernames to send to (separate them with commas).",
+											Style: discordgo.TextInputShort,
+											Required: true,
+										},
+									},
+								},
+								discordgo.ActionsRow {
+									Components: []discordgo.MessageComponent {
+										discordgo.TextInput {
+											CustomID: "note",
+											Label: "Note",
+											Placeholder: "An optional note to put above the email.",
+											Style: discordgo.TextInputParagraph,
+										},
+									},
+								},
+							},
+						},
+					},
+				)
+			}
+		},
+		"unread": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.MessageComponentData().CustomID)
+
+			if data == nil || len(args) < 3 {
+				return
+			}
+
+			owned, err := ownEntries(data.Username, args[1], args[2])
+
+			if err == nil && len(owned) > 0 {
+				owned[0].Read = false
+				err = accounts.SetRead(data.Username, []string {owned[0].ID}, false)
+			}
+
+			webhookError(bot, err)
+
+			if err == nil && len(owned) > 0 {
+				view, err := emailView(owned[0])
+
+				webhookError(bot, err)
+
+				if err == nil {
+					view.Files = nil
+
+					bot.InteractionRespond(
+						interaction.Interaction,
+						&discordgo.InteractionResponse {
+							Type: discordgo.InteractionResponseUpdateMessage,
+							Data: view,
+						},
+					)
+				}
+			}
+		},
+		"recall": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.MessageComponentData().CustomID)
+
+			if data == nil || len(args) < 2 {
+				return
+			}
+
+			content, err := runRecall(data, args[1])
+
+			webhookError(bot, err)
+
+			if err == nil {
+				bot.InteractionRespond(
+					interaction.Interaction,
+					&discordgo.InteractionResponse {
+						Type: discordgo.InteractionResponseUpdateMessage,
+						Data: &discordgo.InteractionResponseData {
+							Content: content,
+							Components: []discordgo.MessageComponent {},
+						},
+					},
+				)
+			}
+		},
+		"star": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.MessageComponentData().CustomID)
+
+			if data == nil || len(args) < 3 {
+				return
+			}
+
+			owned, err := ownEntries(data.Username, args[1], args[2])
+
+			if err == nil && len(owned) > 0 {
+				err = toggleStar(owned[0])
+			}
+
+			webhookError(bot, err)
+
+			if err == nil && len(owned) > 0 {
+				view, err := emailView(owned[0])
+
+				webhookError(bot, err)
+
+				if err == nil {
+					view.Files = nil
+
+					bot.InteractionRespond(
+						interaction.Interaction,
+						&discordgo.InteractionResponse {
+							Type: discordgo.InteractionResponseUpdateMessage,
+							Data: view,
+						},
+					)
+				}
+			}
+		},
+		"delete": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.MessageComponentData().CustomID)
+
+			if data == nil || len(args) < 3 {
+				return
+			}
+
+			owned, err := ownEntries(data.Username, args[1], args[2])
+
+			if err == nil {
+				err = trashEntries(owned)
+			}
+
+			webhookError(bot, err)
+
+			if err == nil {
+				bot.InteractionRespond(
+					interaction.Interaction,
+					&discordgo.InteractionResponse {
+						Type: discordgo.InteractionResponseUpdateMessage,
+						Data: &discordgo.InteractionResponseData {
+							Content: "The email has been moved to the trash.",
+							Embeds: []*discordgo.MessageEmbed {},
+							Components: []discordgo.MessageComponent {},
+						},
+					},
+				)
+			}
+		},
+		"deleteall": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.MessageComponentData().CustomID)
+
+			if data == nil || len(args) < 2 || (args[1] != inboxMailbox && args[1] != sentMailbox) {
+				return
+			}
+
+			found, err := accounts.Entries(data.Username, args[1])
+
+			if err == nil {
+				err = trashEntries(found)
+			}
+
+			webhookError(bot, err)
+
+			if err == nil {
+				updateContent(bot, interaction, fmt.Sprintf("`%v` emails have been moved to the trash.", len(found)))
+			}
+		},
+		"emptytrash": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+
+			if data == nil {
+				return
+			}
+
+			found, err := accounts.Entries(data.Username, trashMailbox)
+
+			if err == nil {
+				err = deleteEntries(data.Username, found)
+			}
+
+			webhookError(bot, err)
+
+			if err == nil {
+				updateContent(bot, interaction, fmt.Sprintf("`%v` emails have been deleted for good.", len(found)))
+			}
+		},
+		"cancel": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			updateContent(bot, interaction, "Nothing was deleted.")
+		},
+	}
+}
+
+func listModals() map[string]func(bot botSession, interaction *discordgo.InteractionCreate) {
+	return map[string]func(bot botSession, interaction *discordgo.InteractionCreate) {
+		"login": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			username, pending := takePendingLogin(interaction.Member.User.ID)
+
+			if !pending {
+				bot.InteractionRespond(
+					interaction.Interaction,
+					&discordgo.InteractionResponse {
+						Type: discordgo.InteractionResponseChannelMessageWithSource,
+						Data: &discordgo.InteractionResponseData {
+							Flags: 1 << 6,
+							Content: "That login has expired, run `/login` again!",
+						},
+					},
+				)
+
+				return
+			}
+
+			data, err := accounts.AccountByUsername(username)
+
+			webhookError(bot, err)
+
+			if err == nil && data != nil {
+				code := modalValue(interaction, "code")
+				passed := true
+
+				if data.TwoFactor.Active {
+					passed, _ = checkPassword(data.TwoFactor.Answer, cleanseAnswer(modalValue(interaction, "answer")))
+				}
+
+				// Using up the step or recovery code is what passes the check,
+				// so the same code can't sign in twice.
+				if passed && data.TOTP.Active {
+					step, valid := checkTOTPCode(data.TOTP.Secret, code, data.TOTP.LastStep, time.Now())
+
+					if valid {
+						passed, err = accounts.UseTOTPStep(username, step)
+					} else if hash := matchRecoveryCode(data.TOTP.RecoveryCodes, code); hash != "" {
+						passed, err = accounts.UseRecoveryCode(username, hash)
+					} else {
+						passed = false
+					}
+
+					webhookError(bot, err)
+
+					if err != nil {
+						return
+					}
+				}
+
+				if !passed {
+					bot.InteractionRespond(
+						interaction.Interaction,
+						&discordgo.InteractionResponse {
+							Type: discordgo.InteractionResponseChannelMessageWithSource,
+							Data: &discordgo.InteractionResponseData {
+								Flags: 1 << 6,
+								Content: "That 2FA answer or code is incorrect!",
+							},
+						},
+					)
+
+					return
+				}
+
+				finishLogin(bot, interaction, username)
+			}
+		},
+		"compose": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+
+			if data == nil {
+				return
+			}
+
+			files := takePendingUploads(interaction.Member.User.ID)
+			args := customIDArgs(interaction.ModalSubmitData().CustomID)
+			sendAt := time.Time {}
+			priority := ""
+
+			if len(args) > 1 {
+				if unix, err := strconv.ParseInt(args[1], 10, 64); err == nil && unix > 0 {
+					sendAt = time.Unix(unix, 0)
+				}
+			}
+
+			if len(args) > 2 {
+				priority = cleansePriority(args[2])
+			}
+
+			sendAndReport(bot, interaction, data, &email {
+				Title: modalValue(interaction, "title"),
+				Recipients: splitUsernames(modalValue(interaction, "usernames")),
+				CC: splitUsernames(modalValue(interaction, "cc")),
+				BCC: splitUsernames(modalValue(interaction, "bcc")),
+				Content: modalValue(interaction, "content"),
+				Priority: priority,
+			}, func() ([]*upload, error) {
+				return downloadAttachments(files)
+			}, sendAt)
+		},
+		"draft": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.ModalSubmitData().CustomID)
+
+			if data == nil || len(args) < 2 {
+				return
+			}
+
+			drafts, err := ownEntries(data.Username, draftsMailbox, args[1])
+
+			if err == nil && len(drafts) > 0 {
+				draft := drafts[0].Email
+				draft.Title = modalValue(interaction, "title")
+				draft.Recipients = splitUsernames(modalValue(interaction, "usernames"))
+				draft.CC = splitUsernames(modalValue(interaction, "cc"))
+				draft.BCC = splitUsernames(modalValue(interaction, "bcc"))
+				draft.Content = modalValue(interaction, "content")
+				err = accounts.SaveEmail(draft)
+
+				// A recalled email can be indexed, so drop it until it's sent again.
+				emailIndex.remove(draft.ID)
+			}
+
+			webhookError(bot, err)
+
+			if err == nil {
+				message := "The draft has been saved."
+
+				if len(drafts) <= 0 {
+					message = "That draft no longer exists."
+				}
+
+				respondPrivately(bot, interaction, message)
+			}
+		},
+		"reply": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.ModalSubmitData().CustomID)
+
+			if data == nil || len(args) < 2 {
+				return
+			}
+
+			entry, err := findEntry(data.Username, args[1])
+			message := &email {}
+			sent := 0
+
+			if err == nil && entry != nil {
+				message = replyEmail(
+					entry.Email,
+					data.Username,
+					len(args) > 2 && args[2] == "all",
+					modalValue(interaction, "title"),
+					modalValue(interaction, "content"),
+				)
+				sent, err = deliverEmail(data, message)
+			}
+
+			webhookError(bot, err)
+
+			if err == nil {
+				reportSent(bot, interaction, sent, message)
+			}
+		},
+		"forward": func(bot botSession, interaction *discordgo.InteractionCreate) {
+			data := signedInAccount(bot, interaction)
+			args := customIDArgs(interaction.ModalSubmitData().CustomID)
+
+			if data == nil || len(args) < 2 {
+				return
+			}
+
+			entry, err := findEntry(data.Username, args[1])
+
+			webhookError(bot, err)
+
+			if err == nil && entry != nil {
+				sendAndReport(bot, interaction, data, forwardedEmail(
+					entry.Email,
+					splitUsernames(modalValue(interaction, "usernames")),
+					modalValue(interaction, "note"),
+				), func() ([]*upload, error) {
+					return copyAttachments(entry.Email)
+				}, time.Time {})
+			}
+		},
+	}
+}
+
+func runReply(bot botSession, interaction *discordgo.InteractionCreate, all bool) {
+	data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+	options := interaction.ApplicationCommandData().Options
+	var entry *mailEntry
+
+	if err == nil {
+		entry, err = findEntry(data.Username, options[0].StringValue())
+	}
+
+	webhookError(bot, err)
+
+	if err != nil {
+		return
+	}
+
+	if entry == nil {
+		bot.InteractionRespond(
+			interaction.Interaction,
+			&discordgo.InteractionResponse {
+				Type: discordgo.InteractionResponseChannelMessageWithSource,
+				Data: &discordgo.InteractionResponseData {
+					Flags: 1 << 6,
+					Content: "There is no email with that ID.",
+				},
+			},
+		)
+
+		return
+	}
+
+	if len(options) < 2 {
+		bot.InteractionRespond(interaction.Interaction, replyModal(entry, all))
+
+		return
+	}
+
+	message := replyEmail(
+		entry.Email,
+		data.Username,
+		all,
+		"",
+		strings.ReplaceAll(options[1].StringValue(), "\\n", "\n"),
+	)
+	sent, err := deliverEmail(data, message)
+
+	webhookError(bot, err)
+
+	if err == nil {
+		reportSent(bot, interaction, sent, message)
+	}
+}
+
+func replyModal(entry *mailEntry, all bool) *discordgo.InteractionResponse {
+	customID := "reply:" + entry.EmailID
+	title := "Reply to @" + entry.Email.Author
+
+	if all {
+		customID += ":all"
+		title = "Reply to Everyone"
+	}
+
+	return &discordgo.InteractionResponse {
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData {
+			CustomID: customID,
+			Title: title,
+			Components: composeInputs(replyEmail(entry.Email, entry.Owner, all, "", "").Title),
+		},
+	}
+}
+
+func emailInputs(draft *email, required bool) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent {
+		discordgo.ActionsRow {
+			Components: []discordgo.MessageComponent {
+				discordgo.TextInput {
+					CustomID: "usernames",
+					Label: "Usernames",
+					Value: strings.Join(draft.Recipients, ", "),
+					Placeholder: "The usernames to send to (separate them with commas).",
+					Style: discordgo.TextInputShort,
+					Required: required,
+				},
+			},
+		},
+		discordgo.ActionsRow {
+			Components: []discordgo.MessageComponent {
+				discordgo.TextInput {
+					CustomID: "cc",
+					Label: "CC",
+					Value: strings.Join(draft.CC, ", "),
+					Placeholder: "The usernames to copy in (separate them with commas).",
+					Style: discordgo.TextInputShort,
+				},
+			},
+		},
+		discordgo.ActionsRow {
+			Components: []discordgo.MessageComponent {
+				discordgo.TextInput {
+					CustomID: "bcc",
+					Label: "BCC",
+					Value: strings.Join(draft.BCC, ", "),
+					Placeholder: "The usernames to copy in without the others knowing.",
+					Style: discordgo.TextInputShort,
+				},
+			},
+		},
+		discordgo.ActionsRow {
+			Components: []discordgo.MessageComponent {
+				discordgo.TextInput {
+					CustomID: "title",
+					Label: "Title",
+					Value: draft.Title,
+					Style: discordgo.TextInputShort,
+					Required: true,
+					MaxLength: emailTitleLength,
+				},
+			},
+		},
+		discordgo.ActionsRow {
+			Components: []discordgo.MessageComponent {
+				discordgo.TextInput {
+					CustomID: "content",
+					Label: "Content",
+					Value: draft.Content,
+					Style: discordgo.TextInputParagraph,
+					Required: required,
+					MaxLength: 4000,
+				},
+			},
+		},
+	}
+}
+
+// sendAndReport sends the email, or schedules it when sendAt is set.
+func sendAndReport(bot botSession, interaction *discordgo.InteractionCreate, sender *account, message *email, loadUploads func() ([]*upload, error), sendAt time.Time) {
+	reportSending(bot, interaction, sender, message, sendAt, func() (int, error) {
+		uploads, err := loadUploads()
+
+		if err != nil {
+			return 0, err
+		}
+
+		if !sendAt.IsZero() {
+			return 0, scheduleEmail(sender, message, uploads, sendAt)
+		}
+
+		return sendWithAttachments(sender, message, uploads)
+	})
+}
+
+// reportSending checks the email's title, then runs send and tells the
+// sender how it went, the same way for every command that sends by hand.
+func reportSending(bot botSession, interaction *discordgo.InteractionCreate, sender *account, message *email, sendAt time.Time, send func() (int, error)) {
+	if utf8.RuneCountInString(message.Title) > emailTitleLength {
+		respondPrivately(bot, interaction, fmt.Sprintf("Email titles cannot be over `%v` letters!", emailTitleLength))
+
+		return
+	}
+
+	bot.InteractionRespond(
+		interaction.Interaction,
+		&discordgo.InteractionResponse {
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData {
+				Flags: 1 << 6,
+				Content: "Sending emails...",
+			},
+		},
+	)
+
+	sent, err := send()
+
+	if err == errStorageFull {
+		bot.InteractionResponseEdit(
+			interaction.AppID,
+			interaction.Interaction,
+			&discordgo.WebhookEdit { Content: fmt.Sprintf(
+				"Those attachments don't fit in your storage (`%v` of `%v` used).",
+				formatStorage(sender.StorageUsed),
+				formatStorage(storageLimit()),
+			) },
+		)
+
+		return
+	}
+
+	webhookError(bot, err)
+
+	content := fmt.Sprintf("`%v` emails were sent, nice!", sent)
+
+	if err == nil && !sendAt.IsZero() {
+		content = fmt.Sprintf(
+			"The email `#%v` will be sent on `%v`. To cancel it, use `/scheduled cancel`.",
+			message.ID,
+			formatSendAt(sendAt, accountLocation(sender)),
+		)
+	}
+
+	bot.InteractionResponseEdit(
+		interaction.AppID,
+		interaction.Interaction,
+		&discordgo.WebhookEdit {
+			Content: content,
+			Components: recallButtons(sent, message),
+		},
+	)
+}
+
+func reportSent(bot botSession, interaction *discordgo.InteractionCreate, sent int, message *email) {
+	bot.InteractionRespond(
+		interaction.Interaction,
+		&discordgo.InteractionResponse {
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData {
+				Flags: 1 << 6,
+				Content: fmt.Sprintf("`%v` emails were sent, nice!", sent),
+				Components: recallButtons(sent, message),
+			},
+		},
+	)
+}
+
+// recallButtons offers to undo a send for as long as it can be recalled.
+func recallButtons(sent int, message *email) []discordgo.MessageComponent {
+	if sent <= 0 || recallWindow() <= 0 {
+		return []discordgo.MessageComponent {}
+	}
+
+	return []discordgo.MessageComponent {
+		discordgo.ActionsRow {
+			Components: []discordgo.MessageComponent {
+				discordgo.Button {
+					Label: "Undo",
+					Style: discordgo.SecondaryButton,
+					CustomID: "recall:" + message.ID,
+				},
+			},
+		},
+	}
+}
+
+func runRecall(user *account, emailID string) (string, error) {
+	owned, err := ownEntries(user.Username, sentMailbox, emailID)
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(owned) <= 0 {
+		return "There is no sent email with that ID.", nil
+	}
+
+	recalled, kept, err := recallEmail(owned[0])
+
+	if err == errRecallExpired {
+		return fmt.Sprintf("Emails can only be recalled within `%v` seconds of sending them.", recallWindow().Seconds()), nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if kept > 0 {
+		return fmt.Sprintf("The email was recalled from `%v` inboxes, but `%v` recipients had already opened it.", recalled, kept), nil
+	}
+
+	return fmt.Sprintf("The email was recalled from `%v` inboxes and put back in your drafts.", recalled), nil
+}
+
+func optionValue(options []*discordgo.ApplicationCommandInteractionDataOption, name string) string {
+	for _, option := range options {
+		if option.Name == name {
+			return option.StringValue()
+		}
+	}
+
+	return ""
+}
+
+func optionBool(options []*discordgo.ApplicationCommandInteractionDataOption, name string) bool {
+	for _, option := range options {
+		if option.Name == name {
+			return option.BoolValue()
+		}
+	}
+
+	return false
+}
+
+func respondPrivately(bot botSession, interaction *discordgo.InteractionCreate, content string) {
+	bot.InteractionRespond(
+		interaction.Interaction,
+		&discordgo.InteractionResponse {
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData {
+				Flags: 1 << 6,
+				Content: content,
+			},
+		},
+	)
+}
+
+// updateContent replaces a message's content and takes its buttons away.
+func updateContent(bot botSession, interaction *discordgo.InteractionCreate, content string) {
+	bot.InteractionRespond(
+		interaction.Interaction,
+		&discordgo.InteractionResponse {
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData {
+				Content: content,
+				Components: []discordgo.MessageComponent {},
+			},
+		},
+	)
+}
+
+func confirmButtons(customID string, label string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent {
+		discordgo.ActionsRow {
+			Components: []discordgo.MessageComponent {
+				discordgo.Button {
+					Label: label,
+					Style: discordgo.DangerButton,
+					CustomID: customID,
+				},
+				discordgo.Button {
+					Label: "Cancel",
+					Style: discordgo.SecondaryButton,
+					CustomID: "cancel",
+				},
+			},
+		},
+	}
+}
+
+func priorityChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := []*discordgo.ApplicationCommandOptionChoice {}
+
+	for _, priority := range priorities {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice {
+			Name: strings.ToUpper(priority[:1]) + priority[1:],
+			Value: priority,
+		})
+	}
+
+	return choices
+}
+
+func emailTypeChoices() []*discordgo.ApplicationCommandOptionChoice {
+	return []*discordgo.ApplicationCommandOptionChoice {
+		{ Name: "Inboxed", Value: "inboxed" },
+		{ Name: "Sent", Value: "sent" },
+	}
+}
+
+func customIDArgs(customID string) []string {
+	return strings.Split(customID, ":")
+}
+
+func signedInAccount(bot botSession, interaction *discordgo.InteractionCreate) *account {
+	data, err := accounts.AccountByUserID(interaction.Member.User.ID)
+
+	webhookError(bot, err)
+
+	if err == nil && data == nil {
+		bot.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse {
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData {
+				Flags: 1 << 6,
+				Content: "Run `/signup` or `/login` first!",
+			},
+		})
+	}
+
+	return data
+}
+
+func composeInputs(title string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent {
+		discordgo.ActionsRow {
+			Components: []discordgo.MessageComponent {
+				discordgo.TextInput {
+					CustomID: "title",
+					Label: "Title",
+					Value: title,
+					Style: discordgo.TextInputShort,
+					Required: true,
+					MaxLength: emailTitleLength,
+				},
+			},
+		},
+		discordgo.ActionsRow {
+			Components: []discordgo.MessageComponent {
+				discordgo.TextInput {
+					CustomID: "content",
+					Label: "Content",
+					Style: discordgo.TextInputParagraph,
+					Required: true,
+				},
+			},
+		},
+	}
+}
+
+func emailView(entry *mailEntry) (*discordgo.InteractionResponseData, error) {
+	siblings, err := accounts.Entries(entry.Owner, entry.Mailbox)
+
+	if err != nil {
+		return nil, err
+	}
+
+	thread, err := threadEntries(entry.Owner, threadOf(entry.Email))
+
+	if err != nil {
+		return nil, err
+	}
+
+	previous := entry.EmailID
+	next := entry.EmailID
+
+	for index, sibling := range siblings {
+		if sibling.EmailID == entry.EmailID {
+			if index > 0 {
+				previous = siblings[index - 1].EmailID
+			}
+
+			if index < len(siblings) - 1 {
+				next = siblings[index + 1].EmailID
+			}
+		}
+	}
+
+	message := entry.Email
+	content := message.Content
+	recipients := []string {}
+
+	// Embed descriptions cap out at 4096 characters, and titles at 256.
+	if runes := []rune(content); len(runes) > 4000 {
+		content = string(runes[:4000]) + "..."
+	}
+
+	title := shortenTitle(message.Title, 256)
+
+	for _, recipient := range message.Recipients {
+		recipients = append(recipients, "`@" + recipient + "`")
+	}
+
+	fields := []*discordgo.MessageEmbedField {
+		{
+			Name: "Author",
+			Value: "`@" + message.Author + "`",
+			Inline: true,
+		},
+		{
+			Name: "Recipients",
+			Value: strings.Join(recipients, ", "),
+			Inline: true,
+		},
+		{
+			Name: "Date",
+			Value: "`" + message.Date + "`",
+			Inline: true,
+		},
+	}
+
+	for index, copied := range [][]string {message.CC, visibleBCC(entry)} {
+		if len(copied) > 0 {
+			usernames := []string {}
+
+			for _, username := range copied {
+				usernames = append(usernames, "`@" + username + "`")
+			}
+
+			fields = append(fields, &discordgo.MessageEmbedField {
+				Name: []string {"CC", "BCC"}[index],
+				Value: strings.Join(usernames, ", "),
+				Inline: true,
+			})
+		}
+	}
+
+	if len(message.Attachments) > 0 {
+		names := []string {}
+
+		for _, file := range message.Attachments {
+			names = append(names, fmt.Sprintf("`%v` (%v)", file.Name, formatStorage(file.Size)))
+		}
+
+		fields = append(fields, &discordgo.MessageEmbedField {
+			Name: "Attachments",
+			Value: strings.Join(names, "\n"),
+		})
+	}
+
+	if priorityRank(message) != 1 {
+		fields = append(fields, &discordgo.MessageEmbedField {
+			Name: "Priority",
+			Value: "`" + message.Priority + "`",
+			Inline: true,
+		})
+	}
+
+	if len(entry.Labels) > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField {
+			Name: "Labels",
+			Value: "`" + strings.Join(entry.Labels, "`, `") + "`",
+			Inline: true,
+		})
+	}
+
+	if len(thread) > 1 {
+		lines := []string {}
+
+		// Only the newest emails fit in a field's 1024 characters, and only
+		// with their titles cut short like in lists.
+		for index, threadEntry := range thread {
+			if index < len(thread) - 5 {
+				continue
+			}
+
+			line := fmt.Sprintf("`#%v` `@%v`: %v", threadEntry.EmailID, threadEntry.Email.Author, listTitle(threadEntry.Email.Title))
+
+			if threadEntry.EmailID == message.ID {
+				line = "**" + line + "**"
+			}
+
+			lines = append(lines, line)
+		}
+
+		fields = append(fields, &discordgo.MessageEmbedField {
+			Name: fmt.Sprintf("Thread (%v)", len(thread)),
+			Value: strings.Join(lines, "\n"),
+		})
+	}
+
+	files, err := attachmentFiles(message, 10)
+	starLabel := "Star"
+
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Starred {
+		starLabel = "Unstar"
+	}
+
+	return &discordgo.InteractionResponseData {
+		Flags: 1 << 6,
+		Files: files,
+		Embeds: []*discordgo.MessageEmbed {
+			{
+				Color: embedColor,
+				Title: title,
+				Description: content,
+				Fields: fields,
+				Footer: &discordgo.MessageEmbedFooter { Text: "#" + message.ID },
+			},
+		},
+		Components: []discordgo.MessageComponent {
+			discordgo.ActionsRow {
+				Components: []discordgo.MessageComponent {
+					discordgo.Button {
+						Label: "Previous",
+						Style: discordgo.SecondaryButton,
+						CustomID: "read:previous:" + entry.Mailbox + ":" + previous,
+						Disabled: previous == entry.EmailID,
+					},
+					discordgo.Button {
+						Label: "Next",
+						Style: discordgo.SecondaryButton,
+						CustomID: "read:next:" + entry.Mailbox + ":" + next,
+						Disabled: next == entry.EmailID,
+					},
+					discordgo.Button {
+						Label: starLabel,
+						Style: discordgo.SecondaryButton,
+						CustomID: "star:" + entry.Mailbox + ":" + message.ID,
+					},
+				},
+			},
+			discordgo.ActionsRow {
+				Components: []discordgo.MessageComponent {
+					discordgo.Button {
+						Label: "Reply",
+						Style: discordgo.PrimaryButton,
+						CustomID: "reply:" + message.ID,
+					},
+					discordgo.Button {
+						Label: "Reply All",
+						Style: discordgo.PrimaryButton,
+						CustomID: "reply:" + message.ID + ":all",
+					},
+					discordgo.Button {
+						Label: "Forward",
+						Style: discordgo.SecondaryButton,
+						CustomID: "forward:" + message.ID,
+					},
+					discordgo.Button {
+						Label: "Delete",
+						Style: discordgo.DangerButton,
+						CustomID: "delete:" + entry.Mailbox + ":" + message.ID,
+					},
+					discordgo.Button {
+						Label: "Mark Unread",
+						Style: discordgo.SecondaryButton,
+						CustomID: "unread:" + entry.Mailbox + ":" + message.ID,
+						Disabled: entry.Mailbox != inboxMailbox || !entry.Read,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+
+// pullSearch runs a search and sends the best matches back as files.
+func pullSearch(bot botSession, interaction *discordgo.InteractionCreate, user *account, mailboxes []string, text string) {
+	query, valid := parseSearch(text, accountLocation(user))
+
+	if !valid {
+		respondPrivately(bot, interaction, "That search isn't valid. Dates look like `before:2026-01-31`, and the only other filters are `from:`, `to:`, `subject:`, `has:attachment` and `is:unread`, `is:read` or `is:starred`.")
+
+		return
+	}
+
+	bot.InteractionRespond(
+		interaction.Interaction,
+		&discordgo.InteractionResponse {
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData {
+				Flags: 1 << 6,
+				Content: "Searching through all emails...",
+			},
+		},
+	)
+
+	pulled := 0
+	files := []*discordgo.File {}
+	results, err := searchEntries(user.Username, mailboxes, query)
+
+	webhookError(bot, err)
+
+	for _, result := range results {
+		if pulled >= 10 || len(files) >= 10 {
+			break
+		}
+
+		actualEmail := result.Entry.Email
+		files = append(files, &discordgo.File {
+			Name: actualEmail.ID + ".txt",
+			Reader: bytes.NewReader([]byte(emailText(result.Entry))),
+		})
+
+		attached, err := attachmentFiles(actualEmail, 10 - len(files))
+
+		webhookError(bot, err)
+
+		files = append(files, attached...)
+
+		pulled++
+	}
+
+	bot.InteractionResponseEdit(
+		interaction.AppID,
+		interaction.Interaction,
+		&discordgo.WebhookEdit {
+			Content: fmt.Sprintf("`%v` emails matched, and the best `%v` were pulled.", len(results), pulled),
+			Files: files,
+		},
+	)
+}
+
+// showEntry opens an email in place of the message it was picked from.
+func showEntry(bot botSession, interaction *discordgo.InteractionCreate, owned []*mailEntry) {
+	if len(owned) <= 0 {
+		bot.InteractionRespond(
+			interaction.Interaction,
+			&discordgo.InteractionResponse {
+				Type: discordgo.InteractionResponseUpdateMessage,
+				Data: &discordgo.InteractionResponseData {
+					Content: "That email no longer exists.",
+					Embeds: []*discordgo.MessageEmbed {},
+					Components: []discordgo.MessageComponent {},
+				},
+			},
+		)
+
+		return
+	}
+
+	err := openEntry(owned[0])
+
+	webhookError(bot, err)
+
+	view, err := emailView(owned[0])
+	responseType := discordgo.InteractionResponseUpdateMessage
+
+	// Edits keep the old message's files, so files get a new message.
+	if err == nil && len(view.Files) > 0 {
+		responseType = discordgo.InteractionResponseChannelMessageWithSource
+	}
+
+	webhookError(bot, err)
+
+	if err == nil {
+		bot.InteractionRespond(
+			interaction.Interaction,
+			&discordgo.InteractionResponse {
+				Type: responseType,
+				Data: view,
+			},
+		)
+	}
+}
+
+// listPage cuts out one page of a listing, moving out-of-range pages to the
+// nearest one since the listing may have shrunk since the page was shown.
+func listPage(entries []*mailEntry, page int) ([]*mailEntry, int, int) {
+	start, end, page, pages := pageBounds(len(entries), page)
+
+	return entries[start:end], page, pages
+}
+
+// pageBounds is listPage for listings that aren't of emails, giving where
+// the page starts and ends instead.
+func pageBounds(count int, page int) (int, int, int, int) {
+	pages := (count + listPageSize - 1) / listPageSize
+
+	if pages < 1 {
+		pages = 1
+	}
+
+	if page >= pages {
+		page = pages - 1
+	}
+
+	if page < 0 {
+		page = 0
+	}
+
+	end := (page + 1) * listPageSize
+
+	if end > count {
+		end = count
+	}
+
+	return page * listPageSize, end, page, pages
+}
+
+// listTitle shortens titles so a full page always fits in an embed field.
+func listTitle(title string) string {
+	return shortenTitle(title, listTitleLength)
+}
+
+func shortenTitle(title string, length int) string {
+	if characters := []rune(title); len(characters) > length {
+		return string(characters[:length - 1]) + "…"
+	}
+
+	return title
+}
+
+// listControls builds the menu that opens a listed email and the buttons
+// that turn the page, where pageID gives the custom ID that shows a page.
+func listControls(mailbox string, entries []*mailEntry, page int, pages int, pageID func(page int) string) []discordgo.MessageComponent {
+	controls := []discordgo.MessageComponent {}
+
+	if len(entries) > 0 {
+		options := []discordgo.SelectMenuOption {}
+
+		for _, entry := range entries {
+			description := "#" + entry.EmailID
+
+			if mailbox == inboxMailbox {
+				description += " from @" + entry.Email.Author
+			}
+
+			options = append(options, discordgo.SelectMenuOption {
+				Label: listTitle(entry.Email.Title),
+				Value: entry.EmailID,
+				Description: description,
+			})
+		}
+
+		controls = append(controls, discordgo.ActionsRow {
+			Components: []discordgo.MessageComponent {
+				discordgo.SelectMenu {
+					CustomID: "open:" + mailbox,
+					Placeholder: "Open an email...",
+					Options: options,
+				},
+			},
+		})
+	}
+
+	return append(controls, pageButtons(page, pages, pageID))
+}
+
+// pageButtons is listControls without the menu, for listings whose items
+// can't be opened.
+func pageButtons(page int, pages int, pageID func(page int) string) discordgo.ActionsRow {
+	return discordgo.ActionsRow {
+		Components: []discordgo.MessageComponent {
+			discordgo.Button {
+				Label: "Previous",
+				Style: discordgo.SecondaryButton,
+				CustomID: pageID(page - 1),
+				Disabled: page <= 0,
+			},
+			discordgo.Button {
+				Label: "Next",
+				Style: discordgo.SecondaryButton,
+				CustomID: pageID(page + 1),
+				Disabled: page >= pages - 1,
+			},
+		},
+	}
+}
+
+func inboxView(user *account, label string, starredOnly bool, folder string, page int) (*discordgo.InteractionResponseData, error) {
+	description := "Emails from contacts are under `Normal`, and unread emails are in **bold**. Replies are grouped under their newest email, with the thread's length next to it. Starred (⭐) and high priority (❗) emails are listed first."
+	inboxed, err := accounts.Entries(user.Username, inboxMailbox)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sent, err := accounts.Entries(user.Username, sentMailbox)
+
+	if err != nil {
+		return nil, err
+	}
+
+	folders := []string {}
+
+	for _, name := range sortedSavedSearches(user) {
+		if user.SavedSearches[name].Pinned {
+			folders = append(folders, fmt.Sprintf("`%v` (%v)", name, countUnread(inFolder(user, name, inboxed))))
+		}
+	}
+
+	if label != "" {
+		inboxed = withLabel(inboxed, label)
+		description = fmt.Sprintf("Only emails labelled `%v` are listed. ", label) + description
+	}
+
+	if starredOnly {
+		starred := []*mailEntry {}
+
+		for _, inboxedEntry := range inboxed {
+			if inboxedEntry.Starred {
+				starred = append(starred, inboxedEntry)
+			}
+		}
+
+		inboxed = starred
+		description = "Only starred emails are listed. " + description
+	}
+
+	if folder != "" {
+		inboxed = inFolder(user, folder, inboxed)
+		description = fmt.Sprintf("Only emails in the `%v` folder are listed. ", folder) + description
+	}
+
+	if len(folders) > 0 {
+		description += "\n\nPinned folders, with their unread emails: " + strings.Join(folders, ", ")
+	}
+
+	unknown := []string {}
+	normal := []string {}
+	unread := map[string]bool {}
+	starred := map[string]bool {}
+	urgent := map[string]bool {}
+	sizes := threadSizes(append(inboxed, sent...))
+	importance := threadImportance(inboxed)
+	latest := latestInThreads(inboxed)
+
+	// Stable so threads of the same importance keep their order.
+	sort.SliceStable(latest, func(i, j int) bool {
+		return importance[threadOf(latest[i].Email)] > importance[threadOf(latest[j].Email)]
+	})
+
+	for _, inboxedEntry := range inboxed {
+		thread := threadOf(inboxedEntry.Email)
+
+		if !inboxedEntry.Read {
+			unread[thread] = true
+		}
+
+		if inboxedEntry.Starred {
+			starred[thread] = true
+		}
+
+		if inboxedEntry.Email.Priority == "high" {
+			urgent[thread] = true
+		}
+	}
+
+	shown, page, pages := listPage(latest, page)
+
+	for _, inboxedEntry := range shown {
+		inboxedEmail := inboxedEntry.Email
+		thread := threadOf(inboxedEmail)
+		title := listTitle(inboxedEmail.Title)
+
+		if unread[thread] {
+			title = "**" + title + "**"
+		}
+
+		if urgent[thread] {
+			title = "❗ " + title
+		}
+
+		if starred[thread] {
+			title = "⭐ " + title
+		}
+
+		if sizes[thread] > 1 {
+			title += fmt.Sprintf(" `(%v)`", sizes[thread])
+		}
+
+		entry := fmt.Sprintf("`#%v` `@%v`: %v", inboxedEmail.ID, inboxedEmail.Author, title)
+
+		if user.ContactList[inboxedEmail.Author] {
+			normal = append(normal, entry)
+		} else {
+			unknown = append(unknown, entry)
+		}
+	}
+
+	if len(unknown) <= 0 {
+		unknown = append(unknown, "`...`")
+	}
+
+	if len(normal) <= 0 {
+		normal = append(normal, "`...`")
+	}
+
+	return &discordgo.InteractionResponseData {
+		Flags: 1 << 6,
+		Content: fmt.Sprintf("You have `%v` unread emails. To open one, pick it below or use the `/read` command.", countUnread(inboxed)),
+		Embeds: []*discordgo.MessageEmbed {
+			{
+				Color: embedColor,
+				Description: description,
+				Fields: []*discordgo.MessageEmbedField {
+					{
+						Name: "<:letter:932398954526687272> Normal",
+						Value: strings.Join(normal, "\n"),
+						Inline: true,
+					},
+					{
+						Name: "<:warning:932177711307300914> Unknown",
+						Value: strings.Join(unknown, "\n"),
+						Inline: true,
+					},
+				},
+				Footer: &discordgo.MessageEmbedFooter { Text: fmt.Sprintf("Page %v of %v", page + 1, pages) },
+			},
+		},
+		Components: listControls(inboxMailbox, shown, page, pages, func(page int) string {
+			return fmt.Sprintf("inbox:%v:%v:%v:%v", page, label, starredOnly, folder)
+		}),
+	}, nil
+}
+
+func sentView(user *account, page int) (*discordgo.InteractionResponseData, error) {
+	sent, err := accounts.Entries(user.Username, sentMailbox)
+
+	if err != nil {
+		return nil, err
+	}
+
+	shown, page, pages := listPage(sent, page)
+	emails := []string {}
+
+	for _, entry := range shown {
+		actualEmail := entry.Email
+		emails = append(emails, fmt.Sprintf("`#%v` `@%v`: %v", actualEmail.ID, actualEmail.Author, listTitle(actualEmail.Title)))
+	}
+
+	if len(emails) <= 0 {
+		emails = append(emails, "`...`")
+	}
+
+	return &discordgo.InteractionResponseData {
+		Flags: 1 << 6,
+		Content: "To open an email, pick it below or use the `/read` command.",
+		Embeds: []*discordgo.MessageEmbed {
+			{
+				Color: embedColor,
+				Description: "These are all emails sent from this account.",
+				Fields: []*discordgo.MessageEmbedField {
+					{
+						Name: "<:letter:932398954526687272> Emails",
+						Value: strings.Join(emails, "\n"),
+						Inline: true,
+					},
+				},
+				Footer: &discordgo.MessageEmbedFooter { Text: fmt.Sprintf("Page %v of %v", page + 1, pages) },
+			},
+		},
+		Components: listControls(sentMailbox, shown, page, pages, func(page int) string {
+			return fmt.Sprintf("sent:%v", page)
+		}),
+	}, nil
+}
+
+// trashView only pages through the trash, since opening a trashed email
+// would offer to delete it again.
+func trashView(user *account, page int) (*discordgo.InteractionResponseData, error) {
+	trashed, err := accounts.Entries(user.Username, trashMailbox)
+
+	if err != nil {
+		return nil, err
+	}
+
+	shown, page, pages := listPage(trashed, page)
+	emails := []string {}
+
+	for _, entry := range shown {
+		emails = append(emails, fmt.Sprintf("`#%v` `@%v`: %v", entry.EmailID, entry.Email.Author, listTitle(entry.Email.Title)))
+	}
+
+	if len(emails) <= 0 {
+		emails = append(emails, "`...`")
+	}
+
+	return &discordgo.InteractionResponseData {
+		Flags: 1 << 6,
+		Content: "To bring an email back, use `/trash restore`.",
+		Embeds: []*discordgo.MessageEmbed {
+			{
+				Color: embedColor,
+				Description: fmt.Sprintf("Emails in the trash are deleted for good after `%v` days.", trashRetention().Hours() / 24),
+				Fields: []*discordgo.MessageEmbedField {
+					{
+						Name: "<:no:932418336229326878> Trash",
+						Value: strings.Join(emails, "\n"),
+						Inline: true,
+					},
+				},
+				Footer: &discordgo.MessageEmbedFooter { Text: fmt.Sprintf("Page %v of %v", page + 1, pages) },
+			},
+		},
+		Components: []discordgo.MessageComponent {
+			pageButtons(page, pages, func(page int) string {
+				return fmt.Sprintf("trash:%v", page)
+			}),
+		},
+	}, nil
+}
+
+func draftsView(user *account, page int) (*discordgo.InteractionResponseData, error) {
+	drafts, err := accounts.Entries(user.Username, draftsMailbox)
+
+	if err != nil {
+		return nil, err
+	}
+
+	shown, page, pages := listPage(drafts, page)
+	lines := []string {}
+
+	for _, entry := range shown {
+		recipients := []string {}
+
+		for _, recipient := range entry.Email.Recipients {
+			recipients = append(recipients, "@" + recipient)
+		}
+
+		if len(recipients) <= 0 {
+			recipients = append(recipients, "...")
+		}
+
+		lines = append(lines, fmt.Sprintf("`#%v` `%v`: %v", entry.EmailID, strings.Join(recipients, ", "), listTitle(entry.Email.Title)))
+	}
+
+	if len(lines) <= 0 {
+		lines = append(lines, "`...`")
+	}
+
+	return &discordgo.InteractionResponseData {
+		Flags: 1 << 6,
+		Content: "To change a draft, use `/draft edit`.",
+		Embeds: []*discordgo.MessageEmbed {
+			{
+				Color: embedColor,
+				Description: "These are all drafts saved on this account.",
+				Fields: []*discordgo.MessageEmbedField {
+					{
+						Name: "<:letter:932398954526687272> Drafts",
+						Value: strings.Join(lines, "\n"),
+						Inline: true,
+					},
+				},
+				Footer: &discordgo.MessageEmbedFooter { Text: fmt.Sprintf("Page %v of %v", page + 1, pages) },
+			},
+		},
+		Components: []discordgo.MessageComponent {
+			pageButtons(page, pages, func(page int) string {
+				return fmt.Sprintf("drafts:%v", page)
+			}),
+		},
+	}, nil
+}
+
+func scheduledView(user *account, page int) (*discordgo.InteractionResponseData, error) {
+	scheduled, err := accounts.Entries(user.Username, scheduledMailbox)
+
+	if err != nil {
+		return nil, err
+	}
+
+	shown, page, pages := listPage(scheduled, page)
+	location := accountLocation(user)
+	lines := []string {}
+
+	// The timezone is left to the description so a page fits in its field.
+	for _, entry := range shown {
+		local := entry.Date.In(location)
+		lines = append(lines, fmt.Sprintf("`#%v` `%v at %v`: %v", entry.EmailID, createDate(local), local.Format("15:04"), listTitle(entry.Email.Title)))
+	}
+
+	if len(lines) <= 0 {
+		lines = append(lines, "`...`")
+	}
+
+	return &discordgo.InteractionResponseData {
+		Flags: 1 << 6,
+		Content: "To stop an email from being sent, use `/scheduled cancel`.",
+		Embeds: []*discordgo.MessageEmbed {
+			{
+				Color: embedColor,
+				Description: fmt.Sprintf("These are all emails waiting to be sent from this account, with times in `%v`.", location.String()),
+				Fields: []*discordgo.MessageEmbedField {
+					{
+						Name: "<:letter:932398954526687272> Scheduled",
+						Value: strings.Join(lines, "\n"),
+						Inline: true,
+					},
+				},
+				Footer: &discordgo.MessageEmbedFooter { Text: fmt.Sprintf("Page %v of %v", page + 1, pages) },
+			},
+		},
+		Components: []discordgo.MessageComponent {
+			pageButtons(page, pages, func(page int) string {
+				return fmt.Sprintf("scheduled:%v", page)
+			}),
+		},
+	}, nil
+}
+
+func labelsView(user *account, page int) (*discordgo.InteractionResponseData, error) {
+	inboxed, err := accounts.Entries(user.Username, inboxMailbox)
+
+	if err != nil {
+		return nil, err
+	}
+
+	names := sortedLabels(user)
+	start, end, page, pages := pageBounds(len(names), page)
+	labels := []string {}
+
+	for _, label := range names[start:end] {
+		labels = append(labels, fmt.Sprintf("`%v` (%v)", label, len(withLabel(inboxed, label))))
+	}
+
+	if len(labels) <= 0 {
+		labels = append(labels, "`...`")
+	}
+
+	return &discordgo.InteractionResponseData {
+		Flags: 1 << 6,
+		Content: "To list the emails under a label, use `/inbox` with its name.",
+		Embeds: []*discordgo.MessageEmbed {
+			{
+				Color: embedColor,
+				Description: "These are all labels on this account, with how many inboxed emails have them.",
+				Fields: []*discordgo.MessageEmbedField {
+					{
+						Name: "<:list:932178353010659338> Labels",
+						Value: strings.Join(labels, "\n"),
+						Inline: true,
+					},
+				},
+				Footer: &discordgo.MessageEmbedFooter { Text: fmt.Sprintf("Page %v of %v", page + 1, pages) },
+			},
+		},
+		Components: []discordgo.MessageComponent {
+			pageButtons(page, pages, func(page int) string {
+				return fmt.Sprintf("labels:%v", page)
+			}),
+		},
+	}, nil
+}
+
+func savedSearchesView(user *account, page int) (*discordgo.InteractionResponseData, error) {
+	names := sortedSavedSearches(user)
+	start, end, page, pages := pageBounds(len(names), page)
+	searches := []string {}
+
+	for _, name := range names[start:end] {
+		search := fmt.Sprintf("`%v`: `%v`", name, listTitle(user.SavedSearches[name].Query))
+
+		if user.SavedSearches[name].Pinned {
+			search = "📌 " + search
+		}
+
+		searches = append(searches, search)
+	}
+
+	if len(searches) <= 0 {
+		searches = append(searches, "`...`")
+	}
+
+	return &discordgo.InteractionResponseData {
+		Flags: 1 << 6,
+		Content: "To run one, use `/savedsearch run` with its name.",
+		Embeds: []*discordgo.MessageEmbed {
+			{
+				Color: embedColor,
+				Description: "These are all searches saved on this account, and the ones pinned as folders in `/inbox` are marked with 📌.",
+				Fields: []*discordgo.MessageEmbedField {
+					{
+						Name: "<:list:932178353010659338> Saved Searches",
+						Value: strings.Join(searches, "\n"),
+						Inline: true,
+					},
+				},
+				Footer: &discordgo.MessageEmbedFooter { Text: fmt.Sprintf("Page %v of %v", page + 1, pages) },
+			},
+		},
+		Components: []discordgo.MessageComponent {
+			pageButtons(page, pages, func(page int) string {
+				return fmt.Sprintf("savedsearches:%v", page)
+			}),
+		},
+	}, nil
+}
+
+func modalValue(interaction *discordgo.InteractionCreate, customID string) string {
+	for _, row := range interaction.ModalSubmitData().Components {
+		if actionsRow, valid := row.(*discordgo.ActionsRow); valid {
+			for _, component := range actionsRow.Components {
+				if input, valid := component.(*discordgo.TextInput); valid && input.CustomID == customID {
+					return input.Value
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// startPendingLogin holds a login until its 2FA form is answered. The timer
+// only clears the login it started, so it can't cut a newer one short.
+func startPendingLogin(userID string, username string) {
+	pending := &pendingLogin {
+		Username: username,
+		Expires: time.Now().Add(pendingLoginTimeout),
+	}
+
+	pendingLoginsLock.Lock()
+	pendingLogins[userID] = pending
+	pendingLoginsLock.Unlock()
+
+	time.AfterFunc(pendingLoginTimeout, func() {
+		pendingLoginsLock.Lock()
+		defer pendingLoginsLock.Unlock()
+
+		if pendingLogins[userID] == pending {
+			delete(pendingLogins, userID)
+		}
+	})
+}
+
+func takePendingLogin(userID string) (string, bool) {
+	pendingLoginsLock.Lock()
+	defer pendingLoginsLock.Unlock()
+
+	pending, valid := pendingLogins[userID]
+	delete(pendingLogins, userID)
+
+	if !valid || time.Now().After(pending.Expires) {
+		return "", false
+	}
+
+	return pending.Username, true
+}
+
+func finishLogin(bot botSession, interaction *discordgo.InteractionCreate, username string) {
+	bot.InteractionRespond(
+		interaction.Interaction,
+		&discordgo.InteractionResponse {
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData {
+				Flags: 1 << 6,
+				Content: "Logging you out of any previous account...",
+			},
+		},
+	)
+
+	err := accounts.ClearUserID(interaction.Member.User.ID)
+
+	webhookError(bot, err)
+
+	if err == nil {
+		bot.InteractionResponseEdit(
+			interaction.AppID,
+			interaction.Interaction,
+			&discordgo.WebhookEdit { Content: "Logging into the account..." },
+		)
+
+		err = accounts.SetUserID(username, interaction.Member.User.ID)
+
+		webhookError(bot, err)
+
+		if err == nil {
+			bot.InteractionResponseEdit(
+				interaction.AppID,
+				interaction.Interaction,
+				&discordgo.WebhookEdit { Content: fmt.Sprintf("You are now logged into `@%v`!", username), },
+			)
+		}
+	}
+}
+
+func formatMonth(month time.Month) string {
+	switch month {
+	case 1: return "January"
+	case 2: return "February"
+	case 3: return "March"
+	case 4: return "April"
+	case 5: return "May"
+	case 6: return "June"
+	case 7: return "July"
+	case 8: return "August"
+	case 9: return "September"
+	case 10: return "October"
+	case 11: return "November"
+	case 12: return "December"
+	}
+
+	return "January"
+}
+
+func formatNumberPos(number int) string {
+	toString := strconv.Itoa(number)
+	split := strings.Split(toString, "") 
+	endNumber, err := strconv.Atoi(split[len(split) - 1])
+
+	if err != nil || endNumber == 1 {
+		return toString + "st"
+	} else if endNumber == 2 {
+		return toString + "nd"
+	} else if endNumber == 3 {
+		return toString + "rd"
+	} else if endNumber > 3 {
+		return toString + "th"
+	}
+
+	return toString + "st"
+}
+
+func createDate(now time.Time) string {
+	return fmt.Sprintf(
+		"%v %v, %v",
+		formatMonth(now.Month()),
+		formatNumberPos(now.Day()),
+		now.Year(),
+	)
+}
+
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+
+	return string(hash), err
+}
+
+func checkPassword(stored string, password string) (bool, bool) {
+	cost, err := bcrypt.Cost([]byte(stored))
+
+	if err != nil {
+		// Accounts made before hashing still hold the plaintext password.
+		return subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1, true
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)) != nil {
+		return false, false
+	}
+
+	return true, cost < bcrypt.DefaultCost
+}
+
+func webhookError(bot botSession, err error) {
+	if err != nil {
+		bot.WebhookExecute(
+			"",
+			"",
+			false,
+			&discordgo.WebhookParams {
+				Embeds: []*discordgo.MessageEmbed {
+					{
+						Color: embedColor,
+						Description: "An error has appeared!",
+						Fields: []*discordgo.MessageEmbedField {
+							{
+								Name: "<:no:932418336229326878> Error",
+								Value: fmt.Sprintf("`%v`", err),
+								Inline: true,
+							},
+						},
+					},
+				},
+			},
+		)
+	}
+}
+
+func cleanseAnswer(answer string) string {
+	return strings.ToLower(strings.TrimSpace(answer))
+}
+
+func showProgress(start, max, size int) (string, float64) {
+	percent := float64(start) / float64(max)
+	progress := math.Round(float64(float64(size) * percent))
+	container := float64(size) - progress
+
+	return fmt.Sprintf(
+		"[%v%v]",
+		strings.Repeat("▇", int(progress)),
+		strings.Repeat(" ឵឵", int(container)),
+	), math.Round(float64(percent * 100))
+}
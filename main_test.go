@@ -0,0 +1,456 @@
+package main
+
+// Imports
+import (
+	"time"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Types
+type (
+	// fakeSession stands in for Discord, keeping every reply the handlers
+	// send so the tests can read them back.
+	fakeSession struct {
+		responses []*discordgo.InteractionResponse
+		edits []*discordgo.WebhookEdit
+		errors []*discordgo.WebhookParams
+	}
+
+	flowStep struct {
+		interaction func() *discordgo.InteractionCreate
+		want string
+	}
+
+	flowCase struct {
+		name string
+		steps []flowStep
+		check func(t *testing.T)
+	}
+)
+
+// Fake Session Functions
+func (session *fakeSession) InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse) error {
+	session.responses = append(session.responses, resp)
+
+	return nil
+}
+
+func (session *fakeSession) InteractionResponseEdit(appID string, interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit) (*discordgo.Message, error) {
+	session.edits = append(session.edits, newresp)
+
+	return &discordgo.Message {}, nil
+}
+
+func (session *fakeSession) WebhookExecute(webhookID string, token string, wait bool, data *discordgo.WebhookParams) (*discordgo.Message, error) {
+	session.errors = append(session.errors, data)
+
+	return &discordgo.Message {}, nil
+}
+
+func (session *fakeSession) ApplicationCommandBulkOverwrite(appID string, guildID string, commands []*discordgo.ApplicationCommand) ([]*discordgo.ApplicationCommand, error) {
+	return commands, nil
+}
+
+// lastReply is what the user would see last, which is the deferred reply's
+// edit for commands that take a while.
+func (session *fakeSession) lastReply() string {
+	if len(session.edits) > 0 {
+		return editContent(session.edits[len(session.edits) - 1])
+	}
+
+	if len(session.responses) > 0 && session.responses[len(session.responses) - 1].Data != nil {
+		return session.responses[len(session.responses) - 1].Data.Content
+	}
+
+	return ""
+}
+
+func editContent(edit *discordgo.WebhookEdit) string {
+	return edit.Content
+}
+
+// Flow Test Functions
+func command(userID string, name string, options ...*discordgo.ApplicationCommandInteractionDataOption) func() *discordgo.InteractionCreate {
+	return func() *discordgo.InteractionCreate {
+		return &discordgo.InteractionCreate {
+			Interaction: &discordgo.Interaction {
+				Type: discordgo.InteractionApplicationCommand,
+				GuildID: "guild",
+				Member: &discordgo.Member { User: &discordgo.User { ID: userID } },
+				Data: discordgo.ApplicationCommandInteractionData {
+					Name: name,
+					Options: options,
+				},
+			},
+		}
+	}
+}
+
+func stringOption(name string, value string) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption {
+		Name: name,
+		Type: discordgo.ApplicationCommandOptionString,
+		Value: value,
+	}
+}
+
+// deleteInboxed deletes the newest email in the account's inbox, whose ID
+// isn't known until it's been sent.
+func deleteInboxed(userID string, username string) func() *discordgo.InteractionCreate {
+	return func() *discordgo.InteractionCreate {
+		id := ""
+		inboxed, err := accounts.Entries(username, inboxMailbox)
+
+		if err == nil && len(inboxed) > 0 {
+			id = inboxed[len(inboxed) - 1].EmailID
+		}
+
+		return command(userID, "delete", stringOption("type", "inboxed"), stringOption("id", id))()
+	}
+}
+
+// recallSent recalls the newest email the account has sent, for the same
+// reason.
+func recallSent(userID string, username string) func() *discordgo.InteractionCreate {
+	return func() *discordgo.InteractionCreate {
+		id := ""
+		sent, err := accounts.Entries(username, sentMailbox)
+
+		if err == nil && len(sent) > 0 {
+			id = sent[len(sent) - 1].EmailID
+		}
+
+		return command(userID, "recall", stringOption("id", id))()
+	}
+}
+
+// sendNewestDraft sends the account's newest draft, for the same reason.
+func sendNewestDraft(userID string, username string) func() *discordgo.InteractionCreate {
+	return func() *discordgo.InteractionCreate {
+		id := ""
+		drafts, err := accounts.Entries(username, draftsMailbox)
+
+		if err == nil && len(drafts) > 0 {
+			id = drafts[len(drafts) - 1].EmailID
+		}
+
+		return command(userID, "draft", subCommandOption("send", stringOption("id", id)))()
+	}
+}
+
+func subCommandOption(name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption {
+		Name: name,
+		Type: discordgo.ApplicationCommandOptionSubCommand,
+		Options: options,
+	}
+}
+
+func signUpSteps(userID string, username string) []flowStep {
+	return []flowStep {
+		{command(userID, "signup", stringOption("username", username), stringOption("password", "password1")), "You've signed up as `@" + username + "`"},
+		{command(userID, "login", stringOption("username", username), stringOption("password", "password1")), "You are now logged into `@" + username + "`!"},
+	}
+}
+
+func joinSteps(groups ...[]flowStep) []flowStep {
+	steps := []flowStep {}
+
+	for _, group := range groups {
+		steps = append(steps, group...)
+	}
+
+	return steps
+}
+
+func mailboxSize(t *testing.T, username string, mailbox string) int {
+	entries, err := accounts.Entries(username, mailbox)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return len(entries)
+}
+
+// checkEmbedLimits fails the test when Discord would reject the embed,
+// which it does for the whole response.
+func checkEmbedLimits(t *testing.T, embed *discordgo.MessageEmbed) {
+	total := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description)
+
+	if length := utf8.RuneCountInString(embed.Title); length > 256 {
+		t.Errorf("embed title is %v characters, over 256", length)
+	}
+
+	if length := utf8.RuneCountInString(embed.Description); length > 4096 {
+		t.Errorf("embed description is %v characters, over 4096", length)
+	}
+
+	if len(embed.Fields) > 25 {
+		t.Errorf("embed has %v fields, over 25", len(embed.Fields))
+	}
+
+	for _, field := range embed.Fields {
+		name, value := utf8.RuneCountInString(field.Name), utf8.RuneCountInString(field.Value)
+		total += name + value
+
+		if name > 256 {
+			t.Errorf("field %q has a %v character name, over 256", field.Name, name)
+		}
+
+		if value > 1024 {
+			t.Errorf("field %q is %v characters, over 1024", field.Name, value)
+		}
+	}
+
+	if total > 6000 {
+		t.Errorf("embed is %v characters in all, over 6000", total)
+	}
+}
+
+func TestHandleInteractionFlows(t *testing.T) {
+	sendToBob := command("1", "email", stringOption("usernames", "bob"), stringOption("title", "Release notes"), stringOption("content", "The release is out."))
+	bobAcceptsAll := []flowStep {{command("2", "protection", stringOption("status", "off")), ""}}
+
+	tests := []flowCase {
+		{
+			name: "signup",
+			steps: []flowStep {
+				{command("1", "signup", stringOption("username", "alice"), stringOption("password", "short")), "cannot be under `8` letters"},
+				{command("1", "signup", stringOption("username", "alice"), stringOption("password", "password1")), "You've signed up as `@alice`"},
+				{command("2", "signup", stringOption("username", "alice"), stringOption("password", "password1")), "That username is already under an account!"},
+			},
+			check: func(t *testing.T) {
+				user, err := accounts.AccountByUsername("alice")
+
+				if err != nil || user == nil || user.Password == "password1" {
+					t.Errorf("AccountByUsername() = %v, %v, want an account with a hashed password", user, err)
+				}
+			},
+		},
+		{
+			name: "login",
+			steps: []flowStep {
+				{command("1", "signup", stringOption("username", "alice"), stringOption("password", "password1")), "You've signed up as `@alice`"},
+				{command("1", "login", stringOption("username", "alice"), stringOption("password", "wrongpass")), "Those credentials don't match any account!"},
+				{command("1", "login", stringOption("username", "alice"), stringOption("password", "password1")), "You are now logged into `@alice`!"},
+			},
+			check: func(t *testing.T) {
+				user, err := accounts.AccountByUserID("1")
+
+				if err != nil || user == nil || user.Username != "alice" {
+					t.Errorf("AccountByUserID() = %v, %v, want alice", user, err)
+				}
+			},
+		},
+		{
+			name: "email",
+			steps: joinSteps(signUpSteps("1", "alice"), signUpSteps("2", "bob"), []flowStep {
+				{sendToBob, "`0` emails were sent"},
+			}, bobAcceptsAll, []flowStep {
+				{command("1", "email", stringOption("usernames", "bob"), stringOption("title", strings.Repeat("a", 101)), stringOption("content", "Too long.")), "Email titles cannot be over `100` letters!"},
+				{sendToBob, "`1` emails were sent, nice!"},
+			}),
+			check: func(t *testing.T) {
+				if inboxed, sent := mailboxSize(t, "bob", inboxMailbox), mailboxSize(t, "alice", sentMailbox); inboxed != 1 || sent != 1 {
+					t.Errorf("bob has %v inboxed and alice %v sent, want 1 each", inboxed, sent)
+				}
+			},
+		},
+		{
+			name: "search",
+			steps: joinSteps(signUpSteps("1", "alice"), signUpSteps("2", "bob"), bobAcceptsAll, []flowStep {
+				{sendToBob, "`1` emails were sent, nice!"},
+				{command("2", "search", stringOption("query", "released")), "`1` emails matched, and the best `1` were pulled."},
+				{command("2", "search", stringOption("query", "from:carol")), "`0` emails matched"},
+				{command("2", "search", stringOption("query", "before:someday")), "That search isn't valid."},
+			}),
+		},
+		{
+			name: "block",
+			steps: joinSteps(signUpSteps("1", "alice"), signUpSteps("2", "bob"), bobAcceptsAll, []flowStep {
+				{command("2", "block", stringOption("username", "nobody")), "That username isn't under any account!"},
+				{command("2", "block", stringOption("username", "alice")), "`@alice` has been blocked."},
+				{sendToBob, "`0` emails were sent"},
+			}),
+			check: func(t *testing.T) {
+				if inboxed := mailboxSize(t, "bob", inboxMailbox); inboxed != 0 {
+					t.Errorf("bob has %v inboxed, want none from a blocked account", inboxed)
+				}
+			},
+		},
+		{
+			name: "delete",
+			steps: joinSteps(signUpSteps("1", "alice"), signUpSteps("2", "bob"), bobAcceptsAll, []flowStep {
+				{sendToBob, "`1` emails were sent, nice!"},
+				{command("2", "delete", stringOption("type", "inboxed"), stringOption("id", "zzzzzz")), "There is no email with that ID."},
+				{deleteInboxed("2", "bob"), "The email has been moved to the trash."},
+			}),
+			check: func(t *testing.T) {
+				if inboxed, trashed := mailboxSize(t, "bob", inboxMailbox), mailboxSize(t, "bob", trashMailbox); inboxed != 0 || trashed != 1 {
+					t.Errorf("bob has %v inboxed and %v trashed, want it moved to the trash", inboxed, trashed)
+				}
+
+				if sent := mailboxSize(t, "alice", sentMailbox); sent != 1 {
+					t.Errorf("alice has %v sent, want her copy kept", sent)
+				}
+			},
+		},
+		{
+			name: "recall",
+			steps: joinSteps(signUpSteps("1", "alice"), signUpSteps("2", "bob"), bobAcceptsAll, []flowStep {
+				{sendToBob, "`1` emails were sent, nice!"},
+				{deleteInboxed("2", "bob"), "The email has been moved to the trash."},
+				{recallSent("1", "alice"), "The email was recalled from `1` inboxes and put back in your drafts."},
+			}),
+			check: func(t *testing.T) {
+				if trashed, drafts := mailboxSize(t, "bob", trashMailbox), mailboxSize(t, "alice", draftsMailbox); trashed != 0 || drafts != 1 {
+					t.Errorf("bob has %v trashed and alice %v drafts, want the unopened copy recalled from the trash", trashed, drafts)
+				}
+			},
+		},
+		{
+			name: "draft",
+			steps: joinSteps(signUpSteps("1", "alice"), signUpSteps("2", "bob"), bobAcceptsAll, []flowStep {
+				{command("1", "draft", subCommandOption("save", stringOption("title", "Plans"))), "The draft has been saved as"},
+				{sendNewestDraft("1", "alice"), "That draft needs a recipient, a title and content before it can be sent."},
+				{command("1", "draft", subCommandOption("save", stringOption("title", "Lunch"), stringOption("usernames", "bob"), stringOption("content", "Noon?"))), "The draft has been saved as"},
+				{sendNewestDraft("1", "alice"), "`1` emails were sent, nice!"},
+			}),
+			check: func(t *testing.T) {
+				if inboxed, drafts := mailboxSize(t, "bob", inboxMailbox), mailboxSize(t, "alice", draftsMailbox); inboxed != 1 || drafts != 1 {
+					t.Errorf("bob has %v inboxed and alice %v drafts, want the finished draft sent and the other kept", inboxed, drafts)
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		accounts = newMemoryStore()
+		emailIndex = newSearchIndex()
+
+		for index, step := range test.steps {
+			session := &fakeSession {}
+
+			// Every step comes straight after the last, so skip the cooldown.
+			cooldowns = map[string]bool {}
+			handleInteraction(session, step.interaction())
+
+			for _, reported := range session.errors {
+				t.Errorf("%s: step %v reported an error: %v", test.name, index, reported.Embeds[0].Fields[0].Value)
+			}
+
+			if reply := session.lastReply(); !strings.Contains(reply, step.want) {
+				t.Errorf("%s: step %v replied %q, want it to contain %q", test.name, index, reply, step.want)
+			}
+		}
+
+		if test.check != nil {
+			test.check(t)
+		}
+	}
+}
+
+func TestDocsFitEmbedLimits(t *testing.T) {
+	session := &fakeSession {}
+	listAppCommands()["docs"].Run(session, command("1", "docs")())
+
+	if len(session.responses) != 1 || session.responses[0].Data == nil {
+		t.Fatalf("/docs sent %v responses, want 1", len(session.responses))
+	}
+
+	for _, embed := range session.responses[0].Data.Embeds {
+		checkEmbedLimits(t, embed)
+	}
+}
+
+// Emails from before titles were capped can still have ones too long for
+// an embed's title.
+func TestEmailViewFitsEmbedLimits(t *testing.T) {
+	accounts = newMemoryStore()
+	sent := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	for index, id := range []string {"aaaaaa", "bbbbbb", "cccccc", "dddddd", "eeeeee", "ffffff"} {
+		err := accounts.SaveEmail(&email {
+			ID: id,
+			Author: "alice",
+			Title: strings.Repeat("a", 300),
+			Recipients: []string {"bob"},
+			Content: "Hello",
+			Sent: sent,
+			ThreadID: "aaaaaa",
+		})
+
+		if err == nil {
+			err = accounts.SaveEntry(&mailEntry {ID: id, Owner: "bob", Mailbox: inboxMailbox, EmailID: id, Date: sent.Add(time.Duration(index) * time.Minute)})
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries := mustEntries(t, accounts, "bob", inboxMailbox)
+	view, err := emailView(entries[len(entries) - 1])
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, embed := range view.Embeds {
+		checkEmbedLimits(t, embed)
+	}
+}
+
+func TestReplyAndForwardTitlesFit(t *testing.T) {
+	original := &email {ID: "aaaaaa", Author: "alice", Title: strings.Repeat("a", emailTitleLength), Recipients: []string {"bob"}}
+
+	for _, message := range []*email {replyEmail(original, "bob", false, "", "Thanks!"), forwardedEmail(original, []string {"carol"}, "")} {
+		if length := utf8.RuneCountInString(message.Title); length > emailTitleLength {
+			t.Errorf("title %q is %v letters, over %v", message.Title, length, emailTitleLength)
+		}
+	}
+}
+
+func TestSendScheduledSkipsCancelled(t *testing.T) {
+	accounts = newMemoryStore()
+	emailIndex = newSearchIndex()
+	createTestAccounts(t, accounts, "alice", "bob")
+
+	if err := accounts.SetProtectInbox("bob", false); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, cancelled := range []bool {true, false} {
+		message := &email {Title: "Later", Recipients: []string {"bob"}, Content: "See you then."}
+
+		if err := scheduleEmail(mustAccount(t, accounts, "alice"), message, nil, time.Now().Add(time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+
+		due, err := accounts.DueEntries(scheduledMailbox, time.Now().Add(time.Hour * 2))
+
+		if err != nil || len(due) != 1 {
+			t.Fatalf("DueEntries() = %v, %v, want the scheduled email", due, err)
+		}
+
+		// The check picked it up, then it was cancelled before being sent.
+		if cancelled {
+			if err = deleteEntries("alice", due); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err = sendScheduled(due[0]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if inboxed, drafts := mailboxSize(t, "bob", inboxMailbox), mailboxSize(t, "alice", draftsMailbox); inboxed != 1 || drafts != 0 {
+		t.Errorf("bob has %v inboxed and alice %v drafts, want only the email that wasn't cancelled sent", inboxed, drafts)
+	}
+}
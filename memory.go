@@ -0,0 +1,412 @@
+package main
+
+// Imports
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Types
+type (
+	memoryStore struct {
+		lock sync.Mutex
+		accounts map[string]*account
+		emails map[string]*email
+		entries map[string]*mailEntry
+		attachments map[string][]byte
+	}
+)
+
+// Memory Functions
+func newMemoryStore() *memoryStore {
+	return &memoryStore {
+		accounts: map[string]*account {},
+		emails: map[string]*email {},
+		entries: map[string]*mailEntry {},
+		attachments: map[string][]byte {},
+	}
+}
+
+func copyAccount(user *account) *account {
+	if user == nil {
+		return nil
+	}
+
+	copied := *user
+	copied.TOTP.RecoveryCodes = append([]string {}, user.TOTP.RecoveryCodes...)
+	copied.ContactList = map[string]bool {}
+	copied.BlockList = map[string]bool {}
+	copied.Labels = map[string]bool {}
+	copied.SavedSearches = map[string]savedSearch {}
+
+	for name := range user.ContactList {
+		copied.ContactList[name] = true
+	}
+
+	for name := range user.BlockList {
+		copied.BlockList[name] = true
+	}
+
+	for name := range user.Labels {
+		copied.Labels[name] = true
+	}
+
+	for name, search := range user.SavedSearches {
+		copied.SavedSearches[name] = search
+	}
+
+	return &copied
+}
+
+func copyEmail(message *email) *email {
+	if message == nil {
+		return nil
+	}
+
+	copied := *message
+	copied.Recipients = append([]string {}, message.Recipients...)
+	copied.CC = append([]string {}, message.CC...)
+	copied.BCC = append([]string {}, message.BCC...)
+	copied.Attachments = append([]attachment {}, message.Attachments...)
+
+	return &copied
+}
+
+func (store *memoryStore) copyEntries(filter func(entry *mailEntry) bool) []*mailEntry {
+	found := []*mailEntry {}
+
+	// Like the other stores, an entry whose email is gone isn't returned.
+	for _, entry := range store.entries {
+		if store.emails[entry.EmailID] != nil && filter(entry) {
+			copied := *entry
+			copied.Labels = append([]string {}, entry.Labels...)
+			copied.Email = copyEmail(store.emails[entry.EmailID])
+			found = append(found, &copied)
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Date.Equal(found[j].Date) {
+			return found[i].ID < found[j].ID
+		}
+
+		return found[i].Date.Before(found[j].Date)
+	})
+
+	return found
+}
+
+func (store *memoryStore) update(username string, change func(user *account)) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	if user, valid := store.accounts[username]; valid {
+		change(user)
+	}
+
+	return nil
+}
+
+func (store *memoryStore) AccountByUserID(userID string) (*account, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	for _, user := range store.accounts {
+		if user.UserID == userID {
+			return copyAccount(user), nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (store *memoryStore) AccountByUsername(username string) (*account, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	return copyAccount(store.accounts[username]), nil
+}
+
+func (store *memoryStore) CreateAccount(user *account) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	store.accounts[user.Username] = copyAccount(user)
+
+	return nil
+}
+
+func (store *memoryStore) SetUserID(username string, userID string) error {
+	return store.update(username, func(user *account) {
+		user.UserID = userID
+	})
+}
+
+func (store *memoryStore) ClearUserID(userID string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	for _, user := range store.accounts {
+		if user.UserID == userID {
+			user.UserID = ""
+		}
+	}
+
+	return nil
+}
+
+func (store *memoryStore) SetPassword(username string, password string) error {
+	return store.update(username, func(user *account) {
+		user.Password = password
+	})
+}
+
+func (store *memoryStore) SetTwoFactor(username string, twoFA twoFactor) error {
+	return store.update(username, func(user *account) {
+		user.TwoFactor = twoFA
+	})
+}
+
+func (store *memoryStore) SetTOTP(username string, authenticator totp) error {
+	return store.update(username, func(user *account) {
+		user.TOTP = authenticator
+		user.TOTP.RecoveryCodes = append([]string {}, authenticator.RecoveryCodes...)
+	})
+}
+
+func (store *memoryStore) UseTOTPStep(username string, step int64) (bool, error) {
+	used := false
+
+	err := store.update(username, func(user *account) {
+		if step > user.TOTP.LastStep {
+			user.TOTP.LastStep = step
+			used = true
+		}
+	})
+
+	return used, err
+}
+
+func (store *memoryStore) UseRecoveryCode(username string, code string) (bool, error) {
+	used := false
+
+	err := store.update(username, func(user *account) {
+		remaining := []string {}
+
+		for _, recoveryCode := range user.TOTP.RecoveryCodes {
+			if recoveryCode == code && !used {
+				used = true
+			} else {
+				remaining = append(remaining, recoveryCode)
+			}
+		}
+
+		user.TOTP.RecoveryCodes = remaining
+	})
+
+	return used, err
+}
+
+func (store *memoryStore) SetProtectInbox(username string, protect bool) error {
+	return store.update(username, func(user *account) {
+		user.ProtectInbox = protect
+	})
+}
+
+func (store *memoryStore) SetContact(username string, contact string, added bool) error {
+	return store.update(username, func(user *account) {
+		if added {
+			user.ContactList[contact] = true
+		} else {
+			delete(user.ContactList, contact)
+		}
+	})
+}
+
+func (store *memoryStore) SetBlocked(username string, blocked string, added bool) error {
+	return store.update(username, func(user *account) {
+		if added {
+			user.BlockList[blocked] = true
+		} else {
+			delete(user.BlockList, blocked)
+		}
+	})
+}
+
+func (store *memoryStore) SetLabel(username string, label string, added bool) error {
+	return store.update(username, func(user *account) {
+		if added {
+			user.Labels[label] = true
+		} else {
+			delete(user.Labels, label)
+		}
+	})
+}
+
+func (store *memoryStore) SetSavedSearch(username string, name string, search *savedSearch) error {
+	return store.update(username, func(user *account) {
+		if search != nil {
+			user.SavedSearches[name] = *search
+		} else {
+			delete(user.SavedSearches, name)
+		}
+	})
+}
+
+func (store *memoryStore) SetTimezone(username string, timezone string) error {
+	return store.update(username, func(user *account) {
+		user.Timezone = timezone
+	})
+}
+
+func (store *memoryStore) SaveEmail(message *email) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	store.emails[message.ID] = copyEmail(message)
+
+	return nil
+}
+
+func (store *memoryStore) EmailByID(id string) (*email, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	return copyEmail(store.emails[id]), nil
+}
+
+func (store *memoryStore) DeleteEmail(id string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	delete(store.emails, id)
+
+	return nil
+}
+
+func (store *memoryStore) SaveEntry(entry *mailEntry) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	copied := *entry
+	copied.Labels = append([]string {}, entry.Labels...)
+	copied.Email = nil
+	store.entries[entry.ID] = &copied
+
+	return nil
+}
+
+func (store *memoryStore) Entries(owner string, mailbox string) ([]*mailEntry, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	return store.copyEntries(func(entry *mailEntry) bool {
+		return entry.Owner == owner && entry.Mailbox == mailbox
+	}), nil
+}
+
+func (store *memoryStore) EntriesByEmail(emailID string) ([]*mailEntry, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	return store.copyEntries(func(entry *mailEntry) bool {
+		return entry.EmailID == emailID
+	}), nil
+}
+
+func (store *memoryStore) DueEntries(mailbox string, before time.Time) ([]*mailEntry, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	return store.copyEntries(func(entry *mailEntry) bool {
+		return entry.Mailbox == mailbox && !entry.Date.After(before)
+	}), nil
+}
+
+func (store *memoryStore) SetRead(owner string, ids []string, read bool) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	for _, id := range ids {
+		if entry, valid := store.entries[id]; valid && entry.Owner == owner {
+			entry.Read = read
+		}
+	}
+
+	return nil
+}
+
+func (store *memoryStore) SetStarred(owner string, ids []string, starred bool) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	for _, id := range ids {
+		if entry, valid := store.entries[id]; valid && entry.Owner == owner {
+			entry.Starred = starred
+		}
+	}
+
+	return nil
+}
+
+func (store *memoryStore) AddStorageUsed(username string, size int64) error {
+	return store.update(username, func(user *account) {
+		user.StorageUsed += size
+	})
+}
+
+func (store *memoryStore) ReserveStorage(username string, size int64, limit int64) (bool, error) {
+	reserved := false
+
+	err := store.update(username, func(user *account) {
+		if user.StorageUsed + size <= limit {
+			user.StorageUsed += size
+			reserved = true
+		}
+	})
+
+	return reserved, err
+}
+
+func (store *memoryStore) SaveAttachment(id string, data []byte) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	store.attachments[id] = append([]byte {}, data...)
+
+	return nil
+}
+
+func (store *memoryStore) AttachmentData(id string) ([]byte, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	if data, valid := store.attachments[id]; valid {
+		return append([]byte {}, data...), nil
+	}
+
+	return nil, nil
+}
+
+func (store *memoryStore) DeleteAttachment(id string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	delete(store.attachments, id)
+
+	return nil
+}
+
+func (store *memoryStore) DeleteEntries(owner string, ids []string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	for _, id := range ids {
+		if entry, valid := store.entries[id]; valid && entry.Owner == owner {
+			delete(store.entries, id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,504 @@
+package main
+
+// Imports
+import (
+	"time"
+	"bytes"
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Types
+type (
+	mongoStore struct {
+		accounts *mongo.Collection
+		emails *mongo.Collection
+		entries *mongo.Collection
+		attachments *gridfs.Bucket
+	}
+)
+
+// Variables
+var (
+	// Emails used to be embedded in the account under these fields.
+	mongoMailboxes = map[string]string {
+		inboxMailbox: "InboxedEmails",
+		sentMailbox: "SentEmails",
+		draftsMailbox: "DraftedEmails",
+	}
+)
+
+// Mongo Functions
+func connectMongo(uri string) (*mongoStore, error) {
+	return connectMongoDatabase(uri, "DiscordBots")
+}
+
+// connectMongoDatabase lets the tests keep to a database of their own.
+func connectMongoDatabase(uri string, name string) (*mongoStore, error) {
+	client, err := mongo.Connect(
+		context.TODO(),
+		options.Client().ApplyURI(uri),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	database := client.Database(name)
+	bucket, err := gridfs.NewBucket(database, options.GridFSBucket().SetName("EtsukoAttachments"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	store := &mongoStore {
+		accounts: database.Collection("EtsukoAccounts"),
+		emails: database.Collection("EtsukoEmails"),
+		entries: database.Collection("EtsukoMailboxes"),
+		attachments: bucket,
+	}
+
+	_, err = store.entries.Indexes().CreateMany(context.TODO(), []mongo.IndexModel {
+		{ Keys: bson.D {{Key: "Owner", Value: 1}, {Key: "Mailbox", Value: 1}, {Key: "Date", Value: 1}} },
+		{ Keys: bson.D {{Key: "EmailID", Value: 1}} },
+		{ Keys: bson.D {{Key: "Mailbox", Value: 1}, {Key: "Date", Value: 1}} },
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Entries from before read tracking count as already read.
+	_, err = store.entries.UpdateMany(
+		context.TODO(),
+		bson.M {"Read": bson.M {"$exists": false}},
+		bson.M {"$set": bson.M {"Read": true}},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return store, store.migrate()
+}
+
+func (store *mongoStore) migrate() error {
+	filter := bson.M {"$or": []bson.M {}}
+
+	for _, field := range mongoMailboxes {
+		filter["$or"] = append(filter["$or"].([]bson.M), bson.M {field: bson.M {"$exists": true}})
+	}
+
+	cursor, err := store.accounts.Find(context.TODO(), filter)
+
+	if err != nil {
+		return err
+	}
+
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var user struct {
+			Username string `bson:"Username"`
+			SentEmails []*email `bson:"SentEmails"`
+			InboxedEmails []*email `bson:"InboxedEmails"`
+			DraftedEmails []*email `bson:"DraftedEmails"`
+		}
+
+		if err = cursor.Decode(&user); err != nil {
+			return err
+		}
+
+		legacy := map[string][]*email {
+			inboxMailbox: user.InboxedEmails,
+			sentMailbox: user.SentEmails,
+			draftsMailbox: user.DraftedEmails,
+		}
+		unset := bson.M {}
+
+		for _, field := range mongoMailboxes {
+			unset[field] = true
+		}
+
+		// Each email is popped off the old list once it's moved, so a rerun
+		// after a crash carries on from there instead of moving it again.
+		for mailbox, emails := range legacy {
+			for index, message := range emails {
+				message.ID, err = uniqueEmailID(func(id string) (bool, error) {
+					existing, err := store.EmailByID(id)
+
+					return existing != nil, err
+				})
+
+				if err != nil {
+					return err
+				}
+
+				message.Sent = parseDate(message.Date).Add(time.Duration(index) * time.Millisecond)
+
+				if err = store.SaveEmail(message); err == nil {
+					err = store.SaveEntry(&mailEntry {
+						ID: newID(),
+						Owner: user.Username,
+						Mailbox: mailbox,
+						EmailID: message.ID,
+						Date: message.Sent,
+						Read: true,
+					})
+				}
+
+				if err == nil {
+					err = store.update(bson.M {"Username": user.Username}, "$pop", bson.M {mongoMailboxes[mailbox]: -1})
+				}
+
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if err = store.update(bson.M {"Username": user.Username}, "$unset", unset); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+func (store *mongoStore) find(filter bson.M) (*account, error) {
+	var result account
+
+	err := store.accounts.FindOne(context.TODO(), filter).Decode(&result)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (store *mongoStore) update(filter bson.M, action string, newValue bson.M) error {
+	_, err := store.accounts.UpdateOne(context.TODO(), filter, bson.M {action: newValue})
+
+	return err
+}
+
+func (store *mongoStore) AccountByUserID(userID string) (*account, error) {
+	return store.find(bson.M {"UserID": userID})
+}
+
+func (store *mongoStore) AccountByUsername(username string) (*account, error) {
+	return store.find(bson.M {"Username": username})
+}
+
+func (store *mongoStore) CreateAccount(user *account) error {
+	_, err := store.accounts.InsertOne(context.TODO(), user)
+
+	return err
+}
+
+func (store *mongoStore) SetUserID(username string, userID string) error {
+	return store.update(bson.M {"Username": username}, "$set", bson.M {"UserID": userID})
+}
+
+func (store *mongoStore) ClearUserID(userID string) error {
+	_, err := store.accounts.UpdateMany(
+		context.TODO(),
+		bson.M {"UserID": userID},
+		bson.M {"$set": bson.M {"UserID": ""}},
+	)
+
+	return err
+}
+
+func (store *mongoStore) SetPassword(username string, password string) error {
+	return store.update(bson.M {"Username": username}, "$set", bson.M {"Password": password})
+}
+
+func (store *mongoStore) SetTwoFactor(username string, twoFA twoFactor) error {
+	return store.update(bson.M {"Username": username}, "$set", bson.M {"2FA": twoFA})
+}
+
+func (store *mongoStore) SetTOTP(username string, authenticator totp) error {
+	return store.update(bson.M {"Username": username}, "$set", bson.M {"TOTP": authenticator})
+}
+
+// The TOTP fields have no bson tags, so they're stored lowercased.
+func (store *mongoStore) UseTOTPStep(username string, step int64) (bool, error) {
+	result, err := store.accounts.UpdateOne(
+		context.TODO(),
+		bson.M {
+			"Username": username,
+			"$or": []bson.M {
+				{"TOTP.laststep": bson.M {"$lt": step}},
+				{"TOTP.laststep": bson.M {"$exists": false}},
+			},
+		},
+		bson.M {"$set": bson.M {"TOTP.laststep": step}},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+func (store *mongoStore) UseRecoveryCode(username string, code string) (bool, error) {
+	result, err := store.accounts.UpdateOne(
+		context.TODO(),
+		bson.M {"Username": username, "TOTP.recoverycodes": code},
+		bson.M {"$pull": bson.M {"TOTP.recoverycodes": code}},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+func (store *mongoStore) SetProtectInbox(username string, protect bool) error {
+	return store.update(bson.M {"Username": username}, "$set", bson.M {"ProtectInbox": protect})
+}
+
+func (store *mongoStore) SetContact(username string, contact string, added bool) error {
+	if added {
+		return store.update(bson.M {"Username": username}, "$set", bson.M {("ContactList." + contact): true})
+	}
+
+	return store.update(bson.M {"Username": username}, "$unset", bson.M {("ContactList." + contact): true})
+}
+
+func (store *mongoStore) SetBlocked(username string, blocked string, added bool) error {
+	if added {
+		return store.update(bson.M {"Username": username}, "$set", bson.M {("BlockList." + blocked): true})
+	}
+
+	return store.update(bson.M {"Username": username}, "$unset", bson.M {("BlockList." + blocked): true})
+}
+
+func (store *mongoStore) SetLabel(username string, label string, added bool) error {
+	if added {
+		return store.update(bson.M {"Username": username}, "$set", bson.M {("Labels." + label): true})
+	}
+
+	return store.update(bson.M {"Username": username}, "$unset", bson.M {("Labels." + label): true})
+}
+
+func (store *mongoStore) SetSavedSearch(username string, name string, search *savedSearch) error {
+	if search != nil {
+		return store.update(bson.M {"Username": username}, "$set", bson.M {("SavedSearches." + name): search})
+	}
+
+	return store.update(bson.M {"Username": username}, "$unset", bson.M {("SavedSearches." + name): true})
+}
+
+func (store *mongoStore) SetTimezone(username string, timezone string) error {
+	return store.update(bson.M {"Username": username}, "$set", bson.M {"Timezone": timezone})
+}
+
+func (store *mongoStore) SaveEmail(message *email) error {
+	_, err := store.emails.ReplaceOne(
+		context.TODO(),
+		bson.M {"_id": message.ID},
+		message,
+		options.Replace().SetUpsert(true),
+	)
+
+	return err
+}
+
+func (store *mongoStore) EmailByID(id string) (*email, error) {
+	var result email
+
+	err := store.emails.FindOne(context.TODO(), bson.M {"_id": id}).Decode(&result)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (store *mongoStore) DeleteEmail(id string) error {
+	_, err := store.emails.DeleteOne(context.TODO(), bson.M {"_id": id})
+
+	return err
+}
+
+func (store *mongoStore) SaveEntry(entry *mailEntry) error {
+	_, err := store.entries.ReplaceOne(
+		context.TODO(),
+		bson.M {"_id": entry.ID},
+		entry,
+		options.Replace().SetUpsert(true),
+	)
+
+	return err
+}
+
+func (store *mongoStore) findEntries(filter bson.M) ([]*mailEntry, error) {
+	found := []*mailEntry {}
+	cursor, err := store.entries.Find(
+		context.TODO(),
+		filter,
+		options.Find().SetSort(bson.D {{Key: "Date", Value: 1}, {Key: "_id", Value: 1}}),
+	)
+
+	if err == nil {
+		err = cursor.All(context.TODO(), &found)
+	}
+
+	if err != nil || len(found) <= 0 {
+		return found, err
+	}
+
+	emailIDs := []string {}
+
+	for _, entry := range found {
+		emailIDs = append(emailIDs, entry.EmailID)
+	}
+
+	emails := []*email {}
+	cursor, err = store.emails.Find(context.TODO(), bson.M {"_id": bson.M {"$in": emailIDs}})
+
+	if err == nil {
+		err = cursor.All(context.TODO(), &emails)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	byID := map[string]*email {}
+
+	for _, message := range emails {
+		byID[message.ID] = message
+	}
+
+	joined := []*mailEntry {}
+
+	for _, entry := range found {
+		if entry.Email = byID[entry.EmailID]; entry.Email != nil {
+			joined = append(joined, entry)
+		}
+	}
+
+	return joined, nil
+}
+
+func (store *mongoStore) Entries(owner string, mailbox string) ([]*mailEntry, error) {
+	return store.findEntries(bson.M {"Owner": owner, "Mailbox": mailbox})
+}
+
+func (store *mongoStore) EntriesByEmail(emailID string) ([]*mailEntry, error) {
+	return store.findEntries(bson.M {"EmailID": emailID})
+}
+
+func (store *mongoStore) DueEntries(mailbox string, before time.Time) ([]*mailEntry, error) {
+	return store.findEntries(bson.M {"Mailbox": mailbox, "Date": bson.M {"$lte": before}})
+}
+
+func (store *mongoStore) SetRead(owner string, ids []string, read bool) error {
+	_, err := store.entries.UpdateMany(
+		context.TODO(),
+		bson.M {"Owner": owner, "_id": bson.M {"$in": ids}},
+		bson.M {"$set": bson.M {"Read": read}},
+	)
+
+	return err
+}
+
+func (store *mongoStore) SetStarred(owner string, ids []string, starred bool) error {
+	_, err := store.entries.UpdateMany(
+		context.TODO(),
+		bson.M {"Owner": owner, "_id": bson.M {"$in": ids}},
+		bson.M {"$set": bson.M {"Starred": starred}},
+	)
+
+	return err
+}
+
+func (store *mongoStore) AddStorageUsed(username string, size int64) error {
+	return store.update(bson.M {"Username": username}, "$inc", bson.M {"StorageUsed": size})
+}
+
+// ReserveStorage only adds the size while it still fits, so two uploads
+// can't both pass the check and go over the limit together.
+func (store *mongoStore) ReserveStorage(username string, size int64, limit int64) (bool, error) {
+	if size > limit {
+		return false, nil
+	}
+
+	result, err := store.accounts.UpdateOne(
+		context.TODO(),
+		bson.M {
+			"Username": username,
+			"$or": []bson.M {
+				{"StorageUsed": bson.M {"$lte": limit - size}},
+
+				// Accounts from before attachments have nothing used yet.
+				{"StorageUsed": bson.M {"$exists": false}},
+			},
+		},
+		bson.M {"$inc": bson.M {"StorageUsed": size}},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+func (store *mongoStore) SaveAttachment(id string, data []byte) error {
+	return store.attachments.UploadFromStreamWithID(id, id, bytes.NewReader(data))
+}
+
+func (store *mongoStore) AttachmentData(id string) ([]byte, error) {
+	var data bytes.Buffer
+
+	_, err := store.attachments.DownloadToStream(id, &data)
+
+	if err == gridfs.ErrFileNotFound {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Bytes(), nil
+}
+
+func (store *mongoStore) DeleteAttachment(id string) error {
+	err := store.attachments.Delete(id)
+
+	if err == gridfs.ErrFileNotFound {
+		return nil
+	}
+
+	return err
+}
+
+func (store *mongoStore) DeleteEntries(owner string, ids []string) error {
+	_, err := store.entries.DeleteMany(
+		context.TODO(),
+		bson.M {"Owner": owner, "_id": bson.M {"$in": ids}},
+	)
+
+	return err
+}
@@ -0,0 +1,217 @@
+package main
+
+// Imports
+import (
+	"fmt"
+	"time"
+	"strings"
+
+	// Hosts don't always ship a timezone database, so embed one.
+	_ "time/tzdata"
+)
+
+// Variables
+var (
+	schedulerInterval = time.Second * 30
+	sendAtLayouts = []string {"2006-01-02 15:04", "2006-01-02"}
+
+	// Longer than a username can be, so nobody can sign up as it.
+	deliveryNoticeAuthor = "Etsuko Mail Delivery System"
+)
+
+// Schedule Functions
+func accountLocation(user *account) *time.Location {
+	location, err := time.LoadLocation(user.Timezone)
+
+	if err != nil {
+		return time.UTC
+	}
+
+	return location
+}
+
+// parseSendAt reads a date and time, or just a time for its next occurrence,
+// in the sender's timezone. An empty value means right away, and anything
+// that isn't in the future is invalid.
+func parseSendAt(value string, location *time.Location, now time.Time) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+
+	if value == "" {
+		return time.Time {}, true
+	}
+
+	now = now.In(location)
+
+	if clock, err := time.ParseInLocation("15:04", value, location); err == nil {
+		sendAt := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, location)
+
+		if !sendAt.After(now) {
+			sendAt = sendAt.AddDate(0, 0, 1)
+		}
+
+		return sendAt, true
+	}
+
+	for _, layout := range sendAtLayouts {
+		if sendAt, err := time.ParseInLocation(layout, value, location); err == nil {
+			return sendAt, sendAt.After(now)
+		}
+	}
+
+	return time.Time {}, false
+}
+
+func formatSendAt(sendAt time.Time, location *time.Location) string {
+	local := sendAt.In(location)
+
+	return createDate(local) + " at " + local.Format("15:04") + " (" + location.String() + ")"
+}
+
+// scheduleEmail keeps the email in the sender's scheduled mailbox, dated
+// for when it should go out.
+func scheduleEmail(sender *account, message *email, uploads []*upload, sendAt time.Time) error {
+	if err := storeUploads(sender, message, uploads); err != nil {
+		return err
+	}
+
+	id, err := newEmailID()
+
+	if err != nil {
+		return err
+	}
+
+	message.ID = id
+	message.Author = sender.Username
+	message.Sent = sendAt
+
+	if err = accounts.SaveEmail(message); err != nil {
+		return err
+	}
+
+	return saveEntry(sender.Username, scheduledMailbox, message)
+}
+
+func scheduleEntry(entry *mailEntry, sendAt time.Time) error {
+	entry.Mailbox = scheduledMailbox
+	entry.Date = sendAt
+
+	return accounts.SaveEntry(entry)
+}
+
+// sendDueEmails delivers every scheduled email whose time has come, with the
+// same checks as sending it by hand. One that fails is reported and the rest
+// still go out.
+func sendDueEmails(bot botSession, now time.Time) error {
+	due, err := accounts.DueEntries(scheduledMailbox, now)
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range due {
+		webhookError(bot, sendScheduled(entry))
+	}
+
+	return nil
+}
+
+// sendScheduled moves the email to the sender's drafts before delivering it,
+// so one that fails partway isn't sent again on the next check. It only
+// leaves the drafts once delivered, otherwise the sender is told it's there.
+// An email cancelled since the check started is left alone.
+func sendScheduled(entry *mailEntry) error {
+	scheduled, err := stillScheduled(entry)
+
+	if err != nil || !scheduled {
+		return err
+	}
+
+	entry.Mailbox = draftsMailbox
+
+	if err = accounts.SaveEntry(entry); err != nil {
+		return err
+	}
+
+	sender, err := accounts.AccountByUsername(entry.Owner)
+
+	if err != nil || sender == nil {
+		return err
+	}
+
+	sent, err := deliverEmail(sender, entry.Email)
+
+	if sent <= 0 {
+		if noticeErr := sendDeliveryNotice(sender, entry.Email); err == nil {
+			err = noticeErr
+		}
+
+		return err
+	}
+
+	return deleteEntries(entry.Owner, []*mailEntry {entry})
+}
+
+func stillScheduled(entry *mailEntry) (bool, error) {
+	found, err := accounts.EntriesByEmail(entry.EmailID)
+
+	if err != nil {
+		return false, err
+	}
+
+	for _, current := range found {
+		if current.ID == entry.ID && current.Mailbox == scheduledMailbox {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// sendDeliveryNotice tells the sender in their inbox that a scheduled email
+// reached nobody. There's no account behind the notice to keep a sent copy.
+func sendDeliveryNotice(sender *account, message *email) error {
+	id, err := newEmailID()
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	notice := &email {
+		ID: id,
+		Author: deliveryNoticeAuthor,
+		Recipients: []string {sender.Username},
+		Title: "Undelivered: " + message.Title,
+		Content: fmt.Sprintf(
+			"Your scheduled email `#%v` couldn't be delivered to any of its recipients, so it was moved to your drafts.",
+			message.ID,
+		),
+		Date: createDate(now),
+		Sent: now,
+		ThreadID: id,
+	}
+
+	if err = accounts.SaveEmail(notice); err != nil {
+		return err
+	}
+
+	emailIndex.add(notice)
+
+	return saveEntry(sender.Username, inboxMailbox, notice)
+}
+
+// runScheduler checks for due emails and expired trash for as long as the
+// bot runs. The first check is right away, so anything that came due while
+// it was offline doesn't wait for the interval.
+func runScheduler(bot botSession) {
+	runScheduledTasks(bot, time.Now())
+
+	for now := range time.Tick(schedulerInterval) {
+		runScheduledTasks(bot, now)
+	}
+}
+
+func runScheduledTasks(bot botSession, now time.Time) {
+	webhookError(bot, sendDueEmails(bot, now))
+	webhookError(bot, purgeTrash(now))
+}
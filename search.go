@@ -0,0 +1,277 @@
+package main
+
+// Imports
+import (
+	"sort"
+	"time"
+	"strings"
+	"unicode"
+)
+
+// Types
+type (
+	searchQuery struct {
+		From string
+		To string
+		Subject string
+		Before time.Time
+		After time.Time
+		HasAttachment bool
+		States []string
+		Text string
+	}
+
+	searchResult struct {
+		Entry *mailEntry
+		Score float64
+	}
+)
+
+// Variables
+var (
+	searchStates = map[string]bool {"unread": true, "read": true, "starred": true}
+)
+
+// Search Functions
+func searchTerms(query string) []string {
+	terms := []string {}
+	term := []rune {}
+	quoted := false
+
+	for _, char := range query {
+		if char == '"' {
+			quoted = !quoted
+		}
+
+		if unicode.IsSpace(char) && !quoted {
+			if len(term) > 0 {
+				terms = append(terms, string(term))
+				term = []rune {}
+			}
+
+			continue
+		}
+
+		term = append(term, char)
+	}
+
+	if len(term) > 0 {
+		terms = append(terms, string(term))
+	}
+
+	return terms
+}
+
+// parseSearch splits a query into its filters and free text, reading dates
+// in the searcher's timezone. Anything that doesn't look like a filter is
+// searched for as text, while a filter with a bad value makes it invalid.
+func parseSearch(query string, location *time.Location) (*searchQuery, bool) {
+	parsed := &searchQuery {}
+	text := []string {}
+
+	for _, term := range searchTerms(query) {
+		parts := strings.SplitN(term, ":", 2)
+
+		if len(parts) < 2 || strings.Trim(parts[1], "\"") == "" {
+			text = append(text, strings.Trim(term, "\""))
+
+			continue
+		}
+
+		key := strings.ToLower(parts[0])
+		value := strings.Trim(parts[1], "\"")
+
+		switch key {
+		case "from":
+			parsed.From = strings.ToLower(strings.TrimPrefix(value, "@"))
+		case "to":
+			parsed.To = strings.ToLower(strings.TrimPrefix(value, "@"))
+		case "subject":
+			parsed.Subject = strings.ToLower(value)
+		case "before", "after":
+			date, err := time.ParseInLocation("2006-01-02", value, location)
+
+			if err != nil {
+				return nil, false
+			}
+
+			if key == "before" {
+				parsed.Before = date
+			} else {
+				parsed.After = date
+			}
+		case "has":
+			if strings.ToLower(value) != "attachment" {
+				return nil, false
+			}
+
+			parsed.HasAttachment = true
+		case "is":
+			if !searchStates[strings.ToLower(value)] {
+				return nil, false
+			}
+
+			parsed.States = append(parsed.States, strings.ToLower(value))
+		default:
+			text = append(text, strings.Trim(term, "\""))
+		}
+	}
+
+	parsed.Text = strings.Join(text, " ")
+
+	return parsed, true
+}
+
+func hasUsername(usernames []string, username string) bool {
+	for _, name := range usernames {
+		if strings.ToLower(name) == username {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesSearch(query *searchQuery, entry *mailEntry) bool {
+	message := entry.Email
+
+	if query.From != "" && strings.ToLower(message.Author) != query.From {
+		return false
+	}
+
+	visible := append(append([]string {}, message.Recipients...), message.CC...)
+
+	if query.To != "" && !hasUsername(append(visible, visibleBCC(entry)...), query.To) {
+		return false
+	}
+
+	if query.Subject != "" && !strings.Contains(strings.ToLower(message.Title), query.Subject) {
+		return false
+	}
+
+	if !query.Before.IsZero() && !message.Sent.Before(query.Before) {
+		return false
+	}
+
+	if !query.After.IsZero() && message.Sent.Before(query.After) {
+		return false
+	}
+
+	if query.HasAttachment && len(message.Attachments) <= 0 {
+		return false
+	}
+
+	for _, state := range query.States {
+		if (state == "unread" && entry.Read) || (state == "read" && !entry.Read) || (state == "starred" && !entry.Starred) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rankEntries ranks entries by how well they match the free text, newest
+// first when they match equally well. Every entry given counts towards the
+// ranking's statistics, not just the ones the filters keep.
+func rankEntries(entries []*mailEntry, query *searchQuery) []*searchResult {
+	results := []*searchResult {}
+	matched := []*mailEntry {}
+	messages := []*email {}
+	ids := []string {}
+	indexed := map[string]bool {}
+	seen := map[string]bool {}
+
+	for _, entry := range entries {
+		if !indexed[entry.EmailID] {
+			indexed[entry.EmailID] = true
+			messages = append(messages, entry.Email)
+			ids = append(ids, entry.EmailID)
+		}
+
+		if !seen[entry.EmailID] && matchesSearch(query, entry) {
+			seen[entry.EmailID] = true
+			matched = append(matched, entry)
+		}
+	}
+
+	emailIndex.ensure(messages)
+	scores := emailIndex.scores(query.Text, ids)
+
+	for _, entry := range matched {
+		if query.Text == "" {
+			results = append(results, &searchResult {entry, 0})
+		} else if score, valid := scores[entry.EmailID]; valid {
+			results = append(results, &searchResult {entry, score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].Entry.Email.Sent.After(results[j].Entry.Email.Sent)
+		}
+
+		return results[i].Score > results[j].Score
+	})
+
+	return results
+}
+
+func searchEntries(owner string, mailboxes []string, query *searchQuery) ([]*searchResult, error) {
+	entries := []*mailEntry {}
+
+	for _, mailbox := range mailboxes {
+		found, err := accounts.Entries(owner, mailbox)
+
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, found...)
+	}
+
+	return rankEntries(entries, query), nil
+}
+
+func sortedSavedSearches(user *account) []string {
+	names := []string {}
+
+	for name := range user.SavedSearches {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// inFolder keeps the entries a pinned search matches, in their own order. A
+// folder whose search was unpinned, or no longer exists or parses, holds
+// nothing.
+func inFolder(user *account, folder string, entries []*mailEntry) []*mailEntry {
+	kept := []*mailEntry {}
+	saved, valid := user.SavedSearches[folder]
+
+	if !valid || !saved.Pinned {
+		return kept
+	}
+
+	query, valid := parseSearch(saved.Query, accountLocation(user))
+
+	if !valid {
+		return kept
+	}
+
+	matched := map[string]bool {}
+
+	for _, result := range rankEntries(entries, query) {
+		matched[result.Entry.ID] = true
+	}
+
+	for _, entry := range entries {
+		if matched[entry.ID] {
+			kept = append(kept, entry)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,134 @@
+package main
+
+// Imports
+import (
+	"time"
+	"testing"
+)
+
+// Search Test Functions
+func testEntries(messages ...*email) []*mailEntry {
+	entries := []*mailEntry {}
+	sent := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for index, message := range messages {
+		message.Sent = sent.Add(time.Duration(index) * time.Hour)
+		entries = append(entries, &mailEntry {
+			ID: "entry-" + message.ID,
+			Owner: "alice",
+			Mailbox: inboxMailbox,
+			EmailID: message.ID,
+			Date: message.Sent,
+			Email: message,
+		})
+	}
+
+	return entries
+}
+
+func rankedIDs(results []*searchResult) []string {
+	ids := []string {}
+
+	for _, result := range results {
+		ids = append(ids, result.Entry.EmailID)
+	}
+
+	return ids
+}
+
+func TestRankEntriesOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		query string
+		messages []*email
+		want []string
+	}{
+		{
+			"title outranks content",
+			"release",
+			[]*email {
+				{ID: "content", Title: "Notes", Content: "the release is out"},
+				{ID: "title", Title: "Release", Content: "it is out"},
+			},
+			[]string {"title", "content"},
+		},
+		{
+			"rarer terms count more",
+			"meeting budget",
+			[]*email {
+				{ID: "common", Title: "Meeting", Content: "a meeting"},
+				{ID: "rare", Title: "Budget", Content: "a budget"},
+				{ID: "other", Title: "Meeting", Content: "another meeting"},
+			},
+			[]string {"rare", "other", "common"},
+		},
+		{
+			"equal matches are newest first",
+			"lunch",
+			[]*email {
+				{ID: "older", Title: "Lunch"},
+				{ID: "newer", Title: "Lunch"},
+			},
+			[]string {"newer", "older"},
+		},
+		{
+			"other forms of a word match",
+			"released",
+			[]*email {
+				{ID: "unrelated", Title: "Hello"},
+				{ID: "stemmed", Title: "Releasing soon"},
+			},
+			[]string {"stemmed"},
+		},
+	}
+
+	for _, test := range tests {
+		emailIndex = newSearchIndex()
+		got := rankedIDs(rankEntries(testEntries(test.messages...), &searchQuery { Text: test.query }))
+
+		if len(got) != len(test.want) {
+			t.Errorf("%s: rankEntries() = %v, want %v", test.name, got, test.want)
+
+			continue
+		}
+
+		for index := range got {
+			if got[index] != test.want[index] {
+				t.Errorf("%s: rankEntries() = %v, want %v", test.name, got, test.want)
+
+				break
+			}
+		}
+	}
+}
+
+// Emails other accounts indexed shouldn't change how a search ranks.
+func TestRankEntriesOwnStatistics(t *testing.T) {
+	emailIndex = newSearchIndex()
+	entries := testEntries(
+		&email {ID: "short", Title: "Invoice"},
+		&email {ID: "long", Title: "Invoice", Content: "for the work done over the last month"},
+	)
+	query := &searchQuery { Text: "invoice" }
+	before := rankEntries(entries, query)
+
+	for _, id := range []string {"a", "b", "c", "d"} {
+		emailIndex.add(&email {ID: "other-" + id, Title: "Invoice invoice", Content: "invoice"})
+	}
+
+	after := rankEntries(entries, query)
+
+	if len(before) != 2 || len(after) != 2 {
+		t.Fatalf("rankEntries() = %v and %v results, want 2", len(before), len(after))
+	}
+
+	for index := range before {
+		if before[index].Entry.EmailID != after[index].Entry.EmailID || before[index].Score != after[index].Score {
+			t.Errorf("result %v changed from %v (%v) to %v (%v)", index, before[index].Entry.EmailID, before[index].Score, after[index].Entry.EmailID, after[index].Score)
+		}
+	}
+
+	if before[0].Entry.EmailID != "short" {
+		t.Errorf("rankEntries() ranked %v first, want short", before[0].Entry.EmailID)
+	}
+}
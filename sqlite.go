@@ -0,0 +1,939 @@
+package main
+
+// Imports
+import (
+	"time"
+	"strconv"
+	"strings"
+	"database/sql"
+	"encoding/json"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Types
+type (
+	sqliteStore struct {
+		db *sql.DB
+	}
+)
+
+// Variables
+var (
+	// Each entry moves the schema up one version, never edit old entries.
+	sqliteMigrations = []string {
+		`CREATE TABLE accounts (
+			username TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL DEFAULT '',
+			password TEXT NOT NULL,
+			sign_up_date TEXT NOT NULL,
+			protect_inbox INTEGER NOT NULL DEFAULT 1,
+			two_factor_active INTEGER NOT NULL DEFAULT 0,
+			two_factor_question TEXT NOT NULL DEFAULT '',
+			two_factor_answer TEXT NOT NULL DEFAULT '',
+			totp_active INTEGER NOT NULL DEFAULT 0,
+			totp_secret TEXT NOT NULL DEFAULT '',
+			totp_recovery_codes TEXT NOT NULL DEFAULT '[]'
+		);
+		CREATE INDEX accounts_user_id ON accounts (user_id);
+		CREATE TABLE contacts (
+			owner TEXT NOT NULL REFERENCES accounts (username),
+			contact TEXT NOT NULL,
+			PRIMARY KEY (owner, contact)
+		);
+		CREATE TABLE blocks (
+			owner TEXT NOT NULL REFERENCES accounts (username),
+			blocked TEXT NOT NULL,
+			PRIMARY KEY (owner, blocked)
+		);
+		CREATE TABLE emails (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			owner TEXT NOT NULL REFERENCES accounts (username),
+			mailbox TEXT NOT NULL,
+			author TEXT NOT NULL,
+			title TEXT NOT NULL,
+			recipients TEXT NOT NULL,
+			content TEXT NOT NULL,
+			date TEXT NOT NULL
+		);
+		CREATE INDEX emails_owner ON emails (owner, mailbox);`,
+		`ALTER TABLE emails RENAME TO legacy_emails;
+		CREATE TABLE emails (
+			id TEXT PRIMARY KEY,
+			author TEXT NOT NULL,
+			title TEXT NOT NULL,
+			recipients TEXT NOT NULL,
+			content TEXT NOT NULL,
+			date TEXT NOT NULL,
+			sent INTEGER NOT NULL
+		);
+		CREATE TABLE mailbox_entries (
+			id TEXT PRIMARY KEY,
+			owner TEXT NOT NULL REFERENCES accounts (username),
+			mailbox TEXT NOT NULL,
+			email_id TEXT NOT NULL REFERENCES emails (id),
+			date INTEGER NOT NULL
+		);
+		CREATE INDEX mailbox_entries_owner ON mailbox_entries (owner, mailbox, date);
+		CREATE INDEX mailbox_entries_email ON mailbox_entries (email_id);
+		INSERT INTO emails (id, author, title, recipients, content, date, sent)
+			SELECT 'legacy-' || id, author, title, recipients, content, date, id FROM legacy_emails;
+		INSERT INTO mailbox_entries (id, owner, mailbox, email_id, date)
+			SELECT 'legacy-' || id, owner, mailbox, 'legacy-' || id, id FROM legacy_emails;
+		DROP TABLE legacy_emails;`,
+		`ALTER TABLE mailbox_entries ADD COLUMN read INTEGER NOT NULL DEFAULT 0;
+		UPDATE mailbox_entries SET read = 1;`,
+		`ALTER TABLE emails ADD COLUMN parent_id TEXT NOT NULL DEFAULT '';
+		ALTER TABLE emails ADD COLUMN thread_id TEXT NOT NULL DEFAULT '';
+		UPDATE emails SET thread_id = id;
+		CREATE INDEX emails_thread ON emails (thread_id);`,
+		`ALTER TABLE emails ADD COLUMN cc TEXT NOT NULL DEFAULT '[]';
+		ALTER TABLE emails ADD COLUMN bcc TEXT NOT NULL DEFAULT '[]';`,
+		`ALTER TABLE accounts ADD COLUMN storage_used INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE emails ADD COLUMN attachments TEXT NOT NULL DEFAULT '[]';
+		CREATE TABLE attachments (
+			id TEXT PRIMARY KEY,
+			data BLOB NOT NULL
+		);`,
+		`ALTER TABLE accounts ADD COLUMN timezone TEXT NOT NULL DEFAULT '';
+		CREATE INDEX mailbox_entries_due ON mailbox_entries (mailbox, date);`,
+		`ALTER TABLE mailbox_entries ADD COLUMN trashed_from TEXT NOT NULL DEFAULT '';`,
+		`ALTER TABLE mailbox_entries ADD COLUMN labels TEXT NOT NULL DEFAULT '[]';
+		CREATE TABLE labels (
+			owner TEXT NOT NULL REFERENCES accounts (username),
+			label TEXT NOT NULL,
+			PRIMARY KEY (owner, label)
+		);`,
+		`ALTER TABLE emails ADD COLUMN priority TEXT NOT NULL DEFAULT '';
+		ALTER TABLE mailbox_entries ADD COLUMN starred INTEGER NOT NULL DEFAULT 0;`,
+		`CREATE TABLE saved_searches (
+			owner TEXT NOT NULL REFERENCES accounts (username),
+			name TEXT NOT NULL,
+			query TEXT NOT NULL,
+			pinned INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (owner, name)
+		);`,
+		`CREATE TABLE recovery_codes (
+			owner TEXT NOT NULL REFERENCES accounts (username),
+			code TEXT NOT NULL,
+			PRIMARY KEY (owner, code)
+		);
+		INSERT OR IGNORE INTO recovery_codes (owner, code)
+			SELECT username, value FROM accounts, json_each(accounts.totp_recovery_codes);
+		ALTER TABLE accounts DROP COLUMN totp_recovery_codes;
+		ALTER TABLE accounts ADD COLUMN totp_last_step INTEGER NOT NULL DEFAULT 0;`,
+		`-- The emails moved out of the old table get regular IDs in fixLegacyEmails.`,
+	}
+
+	// Work a migration can't do in SQL runs in the same transaction, right
+	// after the migration at the same index.
+	sqliteMigrationSteps = map[int]func(tx *sql.Tx) error {
+		12: fixLegacyEmails,
+	}
+)
+
+// SQLite Functions
+func openSQLite(path string) (*sqliteStore, error) {
+	if path == "" {
+		path = "etsuko.db"
+	}
+
+	db, err := sql.Open("sqlite3", path + "?_foreign_keys=on&_busy_timeout=5000")
+
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite only allows one writer, so keep every query on one connection.
+	db.SetMaxOpenConns(1)
+
+	store := &sqliteStore { db: db }
+
+	return store, store.migrate()
+}
+
+func (store *sqliteStore) migrate() error {
+	var version int
+
+	if err := store.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+
+	for ; version < len(sqliteMigrations); version++ {
+		tx, err := store.db.Begin()
+
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(sqliteMigrations[version])
+
+		if step, valid := sqliteMigrationSteps[version]; valid && err == nil {
+			err = step(tx)
+		}
+
+		if err == nil {
+			_, err = tx.Exec("PRAGMA user_version = " + strconv.Itoa(version + 1))
+		}
+
+		if err != nil {
+			tx.Rollback()
+
+			return err
+		}
+
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fixLegacyEmails gives the emails moved out of the old table the same kind
+// of IDs new emails get, in place of the "legacy-" ones, along with any
+// replies threaded under them. They're dated from their old date like in
+// Mongo, offset by their row to keep their order, or by the row alone if the
+// date doesn't parse. Databases without legacy emails are left alone.
+func fixLegacyEmails(tx *sql.Tx) error {
+	rows, err := tx.Query("SELECT id, date FROM emails WHERE id LIKE 'legacy-%'")
+
+	if err != nil {
+		return err
+	}
+
+	legacyIDs := []string {}
+	dates := map[string]string {}
+
+	for rows.Next() {
+		legacyID := ""
+		date := ""
+
+		if err = rows.Scan(&legacyID, &date); err != nil {
+			rows.Close()
+
+			return err
+		}
+
+		legacyIDs = append(legacyIDs, legacyID)
+		dates[legacyID] = date
+	}
+
+	rows.Close()
+
+	if err = rows.Err(); err != nil || len(legacyIDs) <= 0 {
+		return err
+	}
+
+	// The entries point at the old IDs until they're moved over too.
+	if _, err = tx.Exec("PRAGMA defer_foreign_keys = ON"); err != nil {
+		return err
+	}
+
+	for _, legacyID := range legacyIDs {
+		row, err := strconv.ParseInt(strings.TrimPrefix(legacyID, "legacy-"), 10, 64)
+
+		if err != nil {
+			return err
+		}
+
+		sent := time.Unix(0, row)
+
+		if parsed := parseDate(dates[legacyID]); !parsed.IsZero() {
+			sent = parsed.Add(time.Duration(row) * time.Millisecond)
+		}
+
+		id, err := uniqueEmailID(func(id string) (bool, error) {
+			taken := 0
+			err := tx.QueryRow("SELECT COUNT(*) FROM emails WHERE id = ?", id).Scan(&taken)
+
+			return taken > 0, err
+		})
+
+		if err == nil {
+			_, err = tx.Exec("UPDATE emails SET id = ?, sent = ? WHERE id = ?", id, sent.UnixNano(), legacyID)
+		}
+
+		if err == nil {
+			_, err = tx.Exec("UPDATE emails SET thread_id = ? WHERE thread_id = ?", id, legacyID)
+		}
+
+		if err == nil {
+			_, err = tx.Exec("UPDATE emails SET parent_id = ? WHERE parent_id = ?", id, legacyID)
+		}
+
+		// Entries only keep the row as their date until they're moved, like
+		// into the trash.
+		if err == nil {
+			_, err = tx.Exec("UPDATE mailbox_entries SET date = ? WHERE email_id = ? AND date = ?", sent.UnixNano(), legacyID, row)
+		}
+
+		if err == nil {
+			_, err = tx.Exec("UPDATE mailbox_entries SET id = ? WHERE id = ?", newID(), legacyID)
+		}
+
+		if err == nil {
+			_, err = tx.Exec("UPDATE mailbox_entries SET email_id = ? WHERE email_id = ?", id, legacyID)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (store *sqliteStore) find(column string, value string) (*account, error) {
+	user := newAccount("", "")
+
+	err := store.db.QueryRow(
+		`SELECT username, user_id, password, sign_up_date, protect_inbox,
+			two_factor_active, two_factor_question, two_factor_answer,
+			totp_active, totp_secret, totp_last_step, storage_used, timezone
+		FROM accounts WHERE ` + column + ` = ?`,
+		value,
+	).Scan(
+		&user.Username,
+		&user.UserID,
+		&user.Password,
+		&user.SignUpDate,
+		&user.ProtectInbox,
+		&user.TwoFactor.Active,
+		&user.TwoFactor.Question,
+		&user.TwoFactor.Answer,
+		&user.TOTP.Active,
+		&user.TOTP.Secret,
+		&user.TOTP.LastStep,
+		&user.StorageUsed,
+		&user.Timezone,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTP.RecoveryCodes, err = store.loadRecoveryCodes(user.Username); err != nil {
+		return nil, err
+	}
+
+	if err = store.loadList("SELECT contact FROM contacts WHERE owner = ?", user.Username, user.ContactList); err != nil {
+		return nil, err
+	}
+
+	if err = store.loadList("SELECT blocked FROM blocks WHERE owner = ?", user.Username, user.BlockList); err != nil {
+		return nil, err
+	}
+
+	if err = store.loadList("SELECT label FROM labels WHERE owner = ?", user.Username, user.Labels); err != nil {
+		return nil, err
+	}
+
+	if err = store.loadSavedSearches(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (store *sqliteStore) loadSavedSearches(user *account) error {
+	rows, err := store.db.Query("SELECT name, query, pinned FROM saved_searches WHERE owner = ?", user.Username)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		name := ""
+		search := savedSearch {}
+
+		if err = rows.Scan(&name, &search.Query, &search.Pinned); err != nil {
+			return err
+		}
+
+		user.SavedSearches[name] = search
+	}
+
+	return rows.Err()
+}
+
+func (store *sqliteStore) loadRecoveryCodes(username string) ([]string, error) {
+	codes := map[string]bool {}
+	recoveryCodes := []string {}
+
+	if err := store.loadList("SELECT code FROM recovery_codes WHERE owner = ?", username, codes); err != nil {
+		return nil, err
+	}
+
+	for code := range codes {
+		recoveryCodes = append(recoveryCodes, code)
+	}
+
+	return recoveryCodes, nil
+}
+
+func (store *sqliteStore) loadList(query string, username string, list map[string]bool) error {
+	rows, err := store.db.Query(query, username)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		name := ""
+
+		if err = rows.Scan(&name); err != nil {
+			return err
+		}
+
+		list[name] = true
+	}
+
+	return rows.Err()
+}
+
+func (store *sqliteStore) entries(where string, args ...interface {}) ([]*mailEntry, error) {
+	rows, err := store.db.Query(
+		`SELECT mailbox_entries.id, owner, mailbox, email_id, mailbox_entries.date, read, starred, trashed_from, labels,
+			author, title, recipients, cc, bcc, content, emails.date, sent, parent_id, thread_id, attachments, priority
+		FROM mailbox_entries JOIN emails ON emails.id = email_id
+		WHERE ` + where + ` ORDER BY mailbox_entries.date, mailbox_entries.id`,
+		args...,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	found := []*mailEntry {}
+
+	for rows.Next() {
+		entry := &mailEntry { Email: &email {} }
+		date := int64(0)
+		sent := int64(0)
+		labels := ""
+		recipients := ""
+		cc := ""
+		bcc := ""
+		attachments := ""
+
+		err = rows.Scan(
+			&entry.ID,
+			&entry.Owner,
+			&entry.Mailbox,
+			&entry.EmailID,
+			&date,
+			&entry.Read,
+			&entry.Starred,
+			&entry.TrashedFrom,
+			&labels,
+			&entry.Email.Author,
+			&entry.Email.Title,
+			&recipients,
+			&cc,
+			&bcc,
+			&entry.Email.Content,
+			&entry.Email.Date,
+			&sent,
+			&entry.Email.ParentID,
+			&entry.Email.ThreadID,
+			&attachments,
+			&entry.Email.Priority,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err = json.Unmarshal([]byte(labels), &entry.Labels); err != nil {
+			return nil, err
+		}
+
+		if err = unmarshalLists(entry.Email, recipients, cc, bcc, attachments); err != nil {
+			return nil, err
+		}
+
+		entry.Date = time.Unix(0, date)
+		entry.Email.ID = entry.EmailID
+		entry.Email.Sent = time.Unix(0, sent)
+		found = append(found, entry)
+	}
+
+	return found, rows.Err()
+}
+
+func (store *sqliteStore) AccountByUserID(userID string) (*account, error) {
+	return store.find("user_id", userID)
+}
+
+func (store *sqliteStore) AccountByUsername(username string) (*account, error) {
+	return store.find("username", username)
+}
+
+func (store *sqliteStore) CreateAccount(user *account) error {
+	tx, err := store.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO accounts (
+			username, user_id, password, sign_up_date, protect_inbox,
+			two_factor_active, two_factor_question, two_factor_answer,
+			totp_active, totp_secret, totp_last_step
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		user.Username,
+		user.UserID,
+		user.Password,
+		user.SignUpDate,
+		user.ProtectInbox,
+		user.TwoFactor.Active,
+		user.TwoFactor.Question,
+		user.TwoFactor.Answer,
+		user.TOTP.Active,
+		user.TOTP.Secret,
+		user.TOTP.LastStep,
+	)
+
+	for _, code := range user.TOTP.RecoveryCodes {
+		if err == nil {
+			_, err = tx.Exec("INSERT OR IGNORE INTO recovery_codes (owner, code) VALUES (?, ?)", user.Username, code)
+		}
+	}
+
+	for contact := range user.ContactList {
+		if err == nil {
+			_, err = tx.Exec("INSERT INTO contacts (owner, contact) VALUES (?, ?)", user.Username, contact)
+		}
+	}
+
+	for blocked := range user.BlockList {
+		if err == nil {
+			_, err = tx.Exec("INSERT INTO blocks (owner, blocked) VALUES (?, ?)", user.Username, blocked)
+		}
+	}
+
+	for label := range user.Labels {
+		if err == nil {
+			_, err = tx.Exec("INSERT INTO labels (owner, label) VALUES (?, ?)", user.Username, label)
+		}
+	}
+
+	for name, search := range user.SavedSearches {
+		if err == nil {
+			_, err = tx.Exec(
+				"INSERT INTO saved_searches (owner, name, query, pinned) VALUES (?, ?, ?, ?)",
+				user.Username,
+				name,
+				search.Query,
+				search.Pinned,
+			)
+		}
+	}
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (store *sqliteStore) SetUserID(username string, userID string) error {
+	_, err := store.db.Exec("UPDATE accounts SET user_id = ? WHERE username = ?", userID, username)
+
+	return err
+}
+
+func (store *sqliteStore) ClearUserID(userID string) error {
+	_, err := store.db.Exec("UPDATE accounts SET user_id = '' WHERE user_id = ?", userID)
+
+	return err
+}
+
+func (store *sqliteStore) SetPassword(username string, password string) error {
+	_, err := store.db.Exec("UPDATE accounts SET password = ? WHERE username = ?", password, username)
+
+	return err
+}
+
+func (store *sqliteStore) SetTwoFactor(username string, twoFA twoFactor) error {
+	_, err := store.db.Exec(
+		"UPDATE accounts SET two_factor_active = ?, two_factor_question = ?, two_factor_answer = ? WHERE username = ?",
+		twoFA.Active,
+		twoFA.Question,
+		twoFA.Answer,
+		username,
+	)
+
+	return err
+}
+
+func (store *sqliteStore) SetTOTP(username string, authenticator totp) error {
+	tx, err := store.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"UPDATE accounts SET totp_active = ?, totp_secret = ?, totp_last_step = ? WHERE username = ?",
+		authenticator.Active,
+		authenticator.Secret,
+		authenticator.LastStep,
+		username,
+	)
+
+	if err == nil {
+		_, err = tx.Exec("DELETE FROM recovery_codes WHERE owner = ?", username)
+	}
+
+	for _, code := range authenticator.RecoveryCodes {
+		if err == nil {
+			_, err = tx.Exec("INSERT OR IGNORE INTO recovery_codes (owner, code) VALUES (?, ?)", username, code)
+		}
+	}
+
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseTOTPStep and UseRecoveryCode only report success when their own update
+// changed a row, so two logins racing with the same code can't both pass.
+func (store *sqliteStore) UseTOTPStep(username string, step int64) (bool, error) {
+	result, err := store.db.Exec(
+		"UPDATE accounts SET totp_last_step = ? WHERE username = ? AND totp_last_step < ?",
+		step,
+		username,
+		step,
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	changed, err := result.RowsAffected()
+
+	return changed > 0, err
+}
+
+func (store *sqliteStore) UseRecoveryCode(username string, code string) (bool, error) {
+	result, err := store.db.Exec("DELETE FROM recovery_codes WHERE owner = ? AND code = ?", username, code)
+
+	if err != nil {
+		return false, err
+	}
+
+	changed, err := result.RowsAffected()
+
+	return changed > 0, err
+}
+
+func (store *sqliteStore) SetProtectInbox(username string, protect bool) error {
+	_, err := store.db.Exec("UPDATE accounts SET protect_inbox = ? WHERE username = ?", protect, username)
+
+	return err
+}
+
+func (store *sqliteStore) SetContact(username string, contact string, added bool) error {
+	query := "DELETE FROM contacts WHERE owner = ? AND contact = ?"
+
+	if added {
+		query = "INSERT OR IGNORE INTO contacts (owner, contact) VALUES (?, ?)"
+	}
+
+	_, err := store.db.Exec(query, username, contact)
+
+	return err
+}
+
+func (store *sqliteStore) SetBlocked(username string, blocked string, added bool) error {
+	query := "DELETE FROM blocks WHERE owner = ? AND blocked = ?"
+
+	if added {
+		query = "INSERT OR IGNORE INTO blocks (owner, blocked) VALUES (?, ?)"
+	}
+
+	_, err := store.db.Exec(query, username, blocked)
+
+	return err
+}
+
+func (store *sqliteStore) SetSavedSearch(username string, name string, search *savedSearch) error {
+	if search == nil {
+		_, err := store.db.Exec("DELETE FROM saved_searches WHERE owner = ? AND name = ?", username, name)
+
+		return err
+	}
+
+	_, err := store.db.Exec(
+		`INSERT INTO saved_searches (owner, name, query, pinned) VALUES (?, ?, ?, ?)
+		ON CONFLICT (owner, name) DO UPDATE SET query = excluded.query, pinned = excluded.pinned`,
+		username,
+		name,
+		search.Query,
+		search.Pinned,
+	)
+
+	return err
+}
+
+func (store *sqliteStore) SetLabel(username string, label string, added bool) error {
+	query := "DELETE FROM labels WHERE owner = ? AND label = ?"
+
+	if added {
+		query = "INSERT OR IGNORE INTO labels (owner, label) VALUES (?, ?)"
+	}
+
+	_, err := store.db.Exec(query, username, label)
+
+	return err
+}
+
+func (store *sqliteStore) SetTimezone(username string, timezone string) error {
+	_, err := store.db.Exec("UPDATE accounts SET timezone = ? WHERE username = ?", timezone, username)
+
+	return err
+}
+
+func (store *sqliteStore) SaveEmail(message *email) error {
+	lists := []string {}
+	attachments := append([]attachment {}, message.Attachments...)
+
+	for _, list := range []interface {} {
+		append([]string {}, message.Recipients...),
+		append([]string {}, message.CC...),
+		append([]string {}, message.BCC...),
+		attachments,
+	} {
+		encoded, err := json.Marshal(list)
+
+		if err != nil {
+			return err
+		}
+
+		lists = append(lists, string(encoded))
+	}
+
+	_, err := store.db.Exec(
+		`INSERT INTO emails (id, author, title, recipients, cc, bcc, content, date, sent, parent_id, thread_id, attachments, priority)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET author = excluded.author, title = excluded.title, recipients = excluded.recipients,
+			cc = excluded.cc, bcc = excluded.bcc, content = excluded.content, date = excluded.date, sent = excluded.sent,
+			parent_id = excluded.parent_id, thread_id = excluded.thread_id, attachments = excluded.attachments,
+			priority = excluded.priority`,
+		message.ID,
+		message.Author,
+		message.Title,
+		lists[0],
+		lists[1],
+		lists[2],
+		message.Content,
+		message.Date,
+		message.Sent.UnixNano(),
+		message.ParentID,
+		message.ThreadID,
+		lists[3],
+		message.Priority,
+	)
+
+	return err
+}
+
+func (store *sqliteStore) EmailByID(id string) (*email, error) {
+	message := &email { ID: id }
+	sent := int64(0)
+	recipients := ""
+	cc := ""
+	bcc := ""
+	attachments := ""
+
+	err := store.db.QueryRow(
+		"SELECT author, title, recipients, cc, bcc, content, date, sent, parent_id, thread_id, attachments, priority FROM emails WHERE id = ?",
+		id,
+	).Scan(
+		&message.Author,
+		&message.Title,
+		&recipients,
+		&cc,
+		&bcc,
+		&message.Content,
+		&message.Date,
+		&sent,
+		&message.ParentID,
+		&message.ThreadID,
+		&attachments,
+		&message.Priority,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	message.Sent = time.Unix(0, sent)
+
+	return message, unmarshalLists(message, recipients, cc, bcc, attachments)
+}
+
+func unmarshalLists(message *email, recipients string, cc string, bcc string, attachments string) error {
+	err := json.Unmarshal([]byte(recipients), &message.Recipients)
+
+	if err == nil {
+		err = json.Unmarshal([]byte(cc), &message.CC)
+	}
+
+	if err == nil {
+		err = json.Unmarshal([]byte(bcc), &message.BCC)
+	}
+
+	if err == nil {
+		err = json.Unmarshal([]byte(attachments), &message.Attachments)
+	}
+
+	return err
+}
+
+func (store *sqliteStore) AddStorageUsed(username string, size int64) error {
+	_, err := store.db.Exec("UPDATE accounts SET storage_used = storage_used + ? WHERE username = ?", size, username)
+
+	return err
+}
+
+func (store *sqliteStore) ReserveStorage(username string, size int64, limit int64) (bool, error) {
+	result, err := store.db.Exec(
+		"UPDATE accounts SET storage_used = storage_used + ? WHERE username = ? AND storage_used + ? <= ?",
+		size,
+		username,
+		size,
+		limit,
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	changed, err := result.RowsAffected()
+
+	return changed > 0, err
+}
+
+func (store *sqliteStore) SaveAttachment(id string, data []byte) error {
+	_, err := store.db.Exec("INSERT OR REPLACE INTO attachments (id, data) VALUES (?, ?)", id, data)
+
+	return err
+}
+
+func (store *sqliteStore) AttachmentData(id string) ([]byte, error) {
+	var data []byte
+
+	err := store.db.QueryRow("SELECT data FROM attachments WHERE id = ?", id).Scan(&data)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return data, err
+}
+
+func (store *sqliteStore) DeleteAttachment(id string) error {
+	_, err := store.db.Exec("DELETE FROM attachments WHERE id = ?", id)
+
+	return err
+}
+
+func (store *sqliteStore) DeleteEmail(id string) error {
+	_, err := store.db.Exec("DELETE FROM emails WHERE id = ?", id)
+
+	return err
+}
+
+func (store *sqliteStore) SaveEntry(entry *mailEntry) error {
+	labels, err := json.Marshal(append([]string {}, entry.Labels...))
+
+	if err != nil {
+		return err
+	}
+
+	_, err = store.db.Exec(
+		`INSERT INTO mailbox_entries (id, owner, mailbox, email_id, date, read, starred, trashed_from, labels)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET owner = excluded.owner, mailbox = excluded.mailbox, email_id = excluded.email_id,
+			date = excluded.date, read = excluded.read, starred = excluded.starred, trashed_from = excluded.trashed_from,
+			labels = excluded.labels`,
+		entry.ID,
+		entry.Owner,
+		entry.Mailbox,
+		entry.EmailID,
+		entry.Date.UnixNano(),
+		entry.Read,
+		entry.Starred,
+		entry.TrashedFrom,
+		string(labels),
+	)
+
+	return err
+}
+
+func (store *sqliteStore) Entries(owner string, mailbox string) ([]*mailEntry, error) {
+	return store.entries("owner = ? AND mailbox = ?", owner, mailbox)
+}
+
+func (store *sqliteStore) EntriesByEmail(emailID string) ([]*mailEntry, error) {
+	return store.entries("email_id = ?", emailID)
+}
+
+func (store *sqliteStore) DueEntries(mailbox string, before time.Time) ([]*mailEntry, error) {
+	return store.entries("mailbox = ? AND mailbox_entries.date <= ?", mailbox, before.UnixNano())
+}
+
+func (store *sqliteStore) DeleteEntries(owner string, ids []string) error {
+	return store.execEntries("DELETE FROM mailbox_entries", owner, ids)
+}
+
+func (store *sqliteStore) SetRead(owner string, ids []string, read bool) error {
+	return store.execEntries("UPDATE mailbox_entries SET read = ?", owner, ids, read)
+}
+
+func (store *sqliteStore) SetStarred(owner string, ids []string, starred bool) error {
+	return store.execEntries("UPDATE mailbox_entries SET starred = ?", owner, ids, starred)
+}
+
+func (store *sqliteStore) execEntries(query string, owner string, ids []string, args ...interface {}) error {
+	if len(ids) <= 0 {
+		return nil
+	}
+
+	args = append(args, owner)
+
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	_, err := store.db.Exec(
+		query + " WHERE owner = ? AND id IN (?" + strings.Repeat(", ?", len(ids) - 1) + ")",
+		args...,
+	)
+
+	return err
+}
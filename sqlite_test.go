@@ -0,0 +1,90 @@
+package main
+
+// Imports
+import (
+	"time"
+	"strings"
+	"testing"
+	"database/sql"
+	"path/filepath"
+)
+
+// SQLite Test Functions
+
+// A database from before emails had their own table, moved up to the last
+// version before legacy emails got regular IDs, still has them afterwards.
+func TestSQLiteLegacyEmails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "etsuko.db")
+	db, err := sql.Open("sqlite3", path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statements := []string {
+		sqliteMigrations[0],
+		`INSERT INTO accounts (username, password, sign_up_date) VALUES ('alice', 'hash', 'January 2nd, 2021')`,
+		`INSERT INTO emails (owner, mailbox, author, title, recipients, content, date) VALUES
+			('alice', 'inbox', 'bob', 'First', '["alice"]', 'Hello', 'March 3rd, 2021'),
+			('alice', 'inbox', 'bob', 'Second', '["alice"]', 'Hello', 'someday')`,
+	}
+	statements = append(statements, sqliteMigrations[1:12]...)
+	statements = append(
+		statements,
+		`INSERT INTO emails (id, author, title, recipients, content, date, sent, parent_id, thread_id)
+			VALUES ('abc234', 'alice', 'Re: First', '["bob"]', 'Hi', 'March 4th, 2021', 0, 'legacy-1', 'legacy-1')`,
+		"PRAGMA user_version = 12",
+	)
+
+	for _, statement := range statements {
+		if _, err = db.Exec(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	db.Close()
+	firstID := ""
+
+	// Opening it again shouldn't change anything.
+	for run := 0; run < 2; run++ {
+		store, err := openSQLite(path)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entries := mustEntries(t, store, "alice", inboxMailbox)
+		reply, err := store.EmailByID("abc234")
+		store.db.Close()
+
+		if len(entries) != 2 {
+			t.Fatalf("Entries() = %v, want both legacy emails", entries)
+		}
+
+		first := entries[0]
+
+		if first.Email.Title != "First" {
+			first = entries[1]
+		}
+
+		for _, entry := range entries {
+			if strings.HasPrefix(entry.ID, "legacy-") || strings.HasPrefix(entry.EmailID, "legacy-") || len(entry.EmailID) != emailIDLength {
+				t.Errorf("entry %v for email %v still has a legacy ID", entry.ID, entry.EmailID)
+			}
+		}
+
+		if firstID != "" && first.EmailID != firstID {
+			t.Errorf("First's ID changed from %v to %v when opened again", firstID, first.EmailID)
+		}
+
+		firstID = first.EmailID
+
+		if sent := parseDate("March 3rd, 2021").Add(time.Millisecond); !first.Email.Sent.Equal(sent) || !first.Date.Equal(sent) {
+			t.Errorf("First was sent %v and dated %v, want %v", first.Email.Sent, first.Date, sent)
+		}
+
+		if err != nil || reply == nil || reply.ThreadID != first.EmailID || reply.ParentID != first.EmailID {
+			t.Errorf("EmailByID(abc234) = %+v, %v, want it threaded under %v", reply, err, first.EmailID)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+// Imports
+import (
+	"time"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Types
+type (
+	account struct {
+		UserID string `bson:"UserID"`
+		Username string `bson:"Username"`
+		Password string `bson:"Password"`
+		TwoFactor twoFactor `bson:"2FA"`
+		TOTP totp `bson:"TOTP"`
+		SignUpDate string `bson:"SignUpDate"`
+		ContactList map[string]bool `bson:"ContactList"`
+		BlockList map[string]bool `bson:"BlockList"`
+		Labels map[string]bool `bson:"Labels"`
+		SavedSearches map[string]savedSearch `bson:"SavedSearches"`
+		ProtectInbox bool `bson:"ProtectInbox"`
+		StorageUsed int64 `bson:"StorageUsed"`
+		Timezone string `bson:"Timezone"`
+	}
+
+	savedSearch struct {
+		Query string `bson:"Query"`
+		Pinned bool `bson:"Pinned"`
+	}
+
+	// mailEntry places one email in one account's mailbox, so every
+	// recipient shares a single copy of the email itself.
+	mailEntry struct {
+		ID string `bson:"_id"`
+		Owner string `bson:"Owner"`
+		Mailbox string `bson:"Mailbox"`
+		EmailID string `bson:"EmailID"`
+		Date time.Time `bson:"Date"`
+		Read bool `bson:"Read"`
+		Starred bool `bson:"Starred"`
+		TrashedFrom string `bson:"TrashedFrom"`
+		Labels []string `bson:"Labels"`
+		Email *email `bson:"-"`
+	}
+
+	// AccountStore is everything the handlers need from the database.
+	// Lookups return nil (and no error) when nothing matches, and entries
+	// come back oldest first with their Email filled in.
+	AccountStore interface {
+		AccountByUserID(userID string) (*account, error)
+		AccountByUsername(username string) (*account, error)
+		CreateAccount(user *account) error
+		SetUserID(username string, userID string) error
+		ClearUserID(userID string) error
+		SetPassword(username string, password string) error
+		SetTwoFactor(username string, twoFA twoFactor) error
+		SetTOTP(username string, authenticator totp) error
+		UseTOTPStep(username string, step int64) (bool, error)
+		UseRecoveryCode(username string, code string) (bool, error)
+		SetProtectInbox(username string, protect bool) error
+		SetContact(username string, contact string, added bool) error
+		SetBlocked(username string, blocked string, added bool) error
+		SetTimezone(username string, timezone string) error
+		SetLabel(username string, label string, added bool) error
+		SetSavedSearch(username string, name string, search *savedSearch) error
+		SaveEmail(message *email) error
+		EmailByID(id string) (*email, error)
+		DeleteEmail(id string) error
+		SaveEntry(entry *mailEntry) error
+		Entries(owner string, mailbox string) ([]*mailEntry, error)
+		EntriesByEmail(emailID string) ([]*mailEntry, error)
+		DueEntries(mailbox string, before time.Time) ([]*mailEntry, error)
+		DeleteEntries(owner string, ids []string) error
+		SetRead(owner string, ids []string, read bool) error
+		SetStarred(owner string, ids []string, starred bool) error
+		AddStorageUsed(username string, size int64) error
+		ReserveStorage(username string, size int64, limit int64) (bool, error)
+		SaveAttachment(id string, data []byte) error
+		AttachmentData(id string) ([]byte, error)
+		DeleteAttachment(id string) error
+	}
+)
+
+// Mailboxes
+const (
+	inboxMailbox = "inbox"
+	sentMailbox = "sent"
+	draftsMailbox = "drafts"
+	scheduledMailbox = "scheduled"
+	trashMailbox = "trash"
+)
+
+// Variables
+var (
+	accounts AccountStore
+)
+
+// Store Functions
+func newAccount(username string, password string) *account {
+	return &account {
+		UserID: "",
+		Username: username,
+		Password: password,
+		TwoFactor: twoFactor {},
+		TOTP: totp { RecoveryCodes: []string {} },
+		SignUpDate: createDate(time.Now()),
+		ContactList: map[string]bool {},
+		BlockList: map[string]bool {},
+		Labels: map[string]bool {},
+		SavedSearches: map[string]savedSearch {},
+		ProtectInbox: true,
+	}
+}
+
+func newID() string {
+	id := make([]byte, 12)
+	rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
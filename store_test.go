@@ -0,0 +1,457 @@
+package main
+
+// Imports
+import (
+	"os"
+	"time"
+	"bytes"
+	"context"
+	"testing"
+	"path/filepath"
+)
+
+// Types
+type (
+	storeBackend struct {
+		name string
+		open func(t *testing.T) AccountStore
+	}
+
+	storeCase struct {
+		name string
+		run func(t *testing.T, store AccountStore)
+	}
+)
+
+// Variables
+var (
+	testSent = time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	// Mongo is only tested when TestMongoURI is set, in a database that's
+	// dropped afterwards.
+	storeBackends = []storeBackend {
+		{"memory", func(t *testing.T) AccountStore {
+			return newMemoryStore()
+		}},
+		{"sqlite", func(t *testing.T) AccountStore {
+			store, err := openSQLite(filepath.Join(t.TempDir(), "etsuko.db"))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			t.Cleanup(func() { store.db.Close() })
+
+			return store
+		}},
+		{"mongo", func(t *testing.T) AccountStore {
+			uri := os.Getenv("TestMongoURI")
+
+			if uri == "" {
+				t.Skip("TestMongoURI isn't set")
+			}
+
+			store, err := connectMongoDatabase(uri, "EtsukoTest" + newID())
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			t.Cleanup(func() { store.accounts.Database().Drop(context.TODO()) })
+
+			return store
+		}},
+	}
+
+	storeCases = []storeCase {
+		{"accounts", testStoreAccounts},
+		{"entries", testStoreEntries},
+		{"read flags", testStoreReadFlags},
+		{"labels", testStoreLabels},
+		{"attachments", testStoreAttachments},
+		{"saved searches", testStoreSavedSearches},
+		{"totp", testStoreTOTP},
+	}
+)
+
+// Store Test Functions
+func TestStoreConformance(t *testing.T) {
+	for _, backend := range storeBackends {
+		for _, test := range storeCases {
+			backend, test := backend, test
+
+			t.Run(backend.name + "/" + test.name, func(t *testing.T) {
+				test.run(t, backend.open(t))
+			})
+		}
+	}
+}
+
+func createTestAccounts(t *testing.T, store AccountStore, usernames ...string) {
+	for _, username := range usernames {
+		if err := store.CreateAccount(newAccount(username, "hash")); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func mustAccount(t *testing.T, store AccountStore, username string) *account {
+	user, err := store.AccountByUsername(username)
+
+	if err != nil || user == nil {
+		t.Fatalf("AccountByUsername(%q) = %v, %v", username, user, err)
+	}
+
+	return user
+}
+
+func saveTestEntry(t *testing.T, store AccountStore, entry *mailEntry) {
+	if err := store.SaveEntry(entry); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustEntries(t *testing.T, store AccountStore, owner string, mailbox string) []*mailEntry {
+	entries, err := store.Entries(owner, mailbox)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return entries
+}
+
+func saveTestEmail(t *testing.T, store AccountStore, id string) *email {
+	message := &email {
+		ID: id,
+		Author: "alice",
+		Title: "Hello",
+		Recipients: []string {"bob"},
+		Content: "How are you?",
+		Priority: "high",
+		Sent: testSent,
+		ThreadID: id,
+	}
+
+	if err := store.SaveEmail(message); err != nil {
+		t.Fatal(err)
+	}
+
+	return message
+}
+
+func testStoreAccounts(t *testing.T, store AccountStore) {
+	user := newAccount("alice", "hash")
+	user.UserID = "1"
+	user.ContactList["bob"] = true
+	user.BlockList["eve"] = true
+
+	if err := store.CreateAccount(user); err != nil {
+		t.Fatal(err)
+	}
+
+	got := mustAccount(t, store, "alice")
+
+	if got.UserID != "1" || got.Password != "hash" || !got.ProtectInbox || !got.ContactList["bob"] || !got.BlockList["eve"] {
+		t.Errorf("AccountByUsername() = %+v, want the created account", got)
+	}
+
+	if missing, err := store.AccountByUsername("nobody"); missing != nil || err != nil {
+		t.Errorf("AccountByUsername(nobody) = %v, %v, want nil, nil", missing, err)
+	}
+
+	if err := store.SetUserID("alice", "2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := store.AccountByUserID("2"); err != nil || got == nil || got.Username != "alice" {
+		t.Errorf("AccountByUserID(2) = %v, %v, want alice", got, err)
+	}
+
+	if err := store.ClearUserID("2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := store.AccountByUserID("2"); got != nil || err != nil {
+		t.Errorf("AccountByUserID(2) = %v, %v after clearing it, want nil, nil", got, err)
+	}
+
+	for _, err := range []error {
+		store.SetPassword("alice", "newhash"),
+		store.SetTwoFactor("alice", twoFactor {true, "Pet?", "answerhash"}),
+		store.SetProtectInbox("alice", false),
+		store.SetTimezone("alice", "Asia/Tokyo"),
+		store.SetContact("alice", "carol", true),
+		store.SetContact("alice", "bob", false),
+		store.SetBlocked("alice", "eve", false),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got = mustAccount(t, store, "alice")
+
+	if got.Password != "newhash" || !got.TwoFactor.Active || got.TwoFactor.Question != "Pet?" || got.ProtectInbox || got.Timezone != "Asia/Tokyo" {
+		t.Errorf("AccountByUsername() = %+v, want the updated settings", got)
+	}
+
+	if !got.ContactList["carol"] || got.ContactList["bob"] || got.BlockList["eve"] {
+		t.Errorf("contacts = %v, blocks = %v, want only carol as a contact", got.ContactList, got.BlockList)
+	}
+}
+
+func testStoreEntries(t *testing.T, store AccountStore) {
+	createTestAccounts(t, store, "alice", "bob")
+	message := saveTestEmail(t, store, "abc234")
+
+	got, err := store.EmailByID(message.ID)
+
+	if err != nil || got == nil || got.Title != message.Title || got.Content != message.Content || got.Priority != "high" || !got.Sent.Equal(testSent) {
+		t.Fatalf("EmailByID() = %+v, %v, want the saved email", got, err)
+	}
+
+	if len(got.Recipients) != 1 || got.Recipients[0] != "bob" {
+		t.Errorf("EmailByID() recipients = %v, want [bob]", got.Recipients)
+	}
+
+	if missing, err := store.EmailByID("zzzzzz"); missing != nil || err != nil {
+		t.Errorf("EmailByID(zzzzzz) = %v, %v, want nil, nil", missing, err)
+	}
+
+	saveTestEntry(t, store, &mailEntry {ID: "newer", Owner: "bob", Mailbox: inboxMailbox, EmailID: message.ID, Date: testSent.Add(time.Hour * 2)})
+	saveTestEntry(t, store, &mailEntry {ID: "older", Owner: "bob", Mailbox: inboxMailbox, EmailID: message.ID, Date: testSent.Add(time.Hour)})
+	saveTestEntry(t, store, &mailEntry {ID: "sent", Owner: "alice", Mailbox: sentMailbox, EmailID: message.ID, Date: testSent, Read: true})
+
+	inboxed := mustEntries(t, store, "bob", inboxMailbox)
+
+	if len(inboxed) != 2 || inboxed[0].ID != "older" || inboxed[1].ID != "newer" {
+		t.Fatalf("Entries() = %v, want older then newer", inboxed)
+	}
+
+	if inboxed[0].Email == nil || inboxed[0].Email.Title != message.Title || !inboxed[0].Date.Equal(testSent.Add(time.Hour)) {
+		t.Errorf("Entries()[0] = %+v, want its email and date filled in", inboxed[0])
+	}
+
+	if byEmail, err := store.EntriesByEmail(message.ID); err != nil || len(byEmail) != 3 {
+		t.Errorf("EntriesByEmail() = %v entries, %v, want 3", len(byEmail), err)
+	}
+
+	if due, err := store.DueEntries(inboxMailbox, testSent.Add(time.Minute * 90)); err != nil || len(due) != 1 || due[0].ID != "older" {
+		t.Errorf("DueEntries() = %v, %v, want only older", due, err)
+	}
+
+	if err = store.DeleteEntries("alice", []string {"older"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if inboxed = mustEntries(t, store, "bob", inboxMailbox); len(inboxed) != 2 {
+		t.Errorf("DeleteEntries() removed another account's entry")
+	}
+
+	if err = store.DeleteEntries("bob", []string {"older"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if inboxed = mustEntries(t, store, "bob", inboxMailbox); len(inboxed) != 1 || inboxed[0].ID != "newer" {
+		t.Errorf("Entries() = %v after deleting older, want only newer", inboxed)
+	}
+
+	if err = store.DeleteEntries("bob", []string {"newer"}); err == nil {
+		err = store.DeleteEntries("alice", []string {"sent"})
+	}
+
+	if err == nil {
+		err = store.DeleteEmail(message.ID)
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gone, err := store.EmailByID(message.ID); gone != nil || err != nil {
+		t.Errorf("EmailByID() = %v, %v after deleting it, want nil, nil", gone, err)
+	}
+}
+
+func testStoreReadFlags(t *testing.T, store AccountStore) {
+	createTestAccounts(t, store, "alice", "bob")
+	message := saveTestEmail(t, store, "abc234")
+	saveTestEntry(t, store, &mailEntry {ID: "entry", Owner: "bob", Mailbox: inboxMailbox, EmailID: message.ID, Date: testSent})
+
+	if err := store.SetRead("alice", []string {"entry"}, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if entry := mustEntries(t, store, "bob", inboxMailbox)[0]; entry.Read {
+		t.Errorf("SetRead() changed another account's entry")
+	}
+
+	if err := store.SetRead("bob", []string {"entry"}, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SetStarred("bob", []string {"entry"}, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if entry := mustEntries(t, store, "bob", inboxMailbox)[0]; !entry.Read || !entry.Starred {
+		t.Errorf("entry = %+v, want it read and starred", entry)
+	}
+
+	if err := store.SetRead("bob", []string {"entry"}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SetStarred("bob", []string {"entry"}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if entry := mustEntries(t, store, "bob", inboxMailbox)[0]; entry.Read || entry.Starred {
+		t.Errorf("entry = %+v, want it unread and unstarred", entry)
+	}
+}
+
+func testStoreLabels(t *testing.T, store AccountStore) {
+	createTestAccounts(t, store, "alice", "bob")
+
+	for _, label := range []string {"work", "home"} {
+		if err := store.SetLabel("bob", label, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := store.SetLabel("bob", "home", false); err != nil {
+		t.Fatal(err)
+	}
+
+	if labels := mustAccount(t, store, "bob").Labels; !labels["work"] || labels["home"] {
+		t.Errorf("Labels = %v, want only work", labels)
+	}
+
+	message := saveTestEmail(t, store, "abc234")
+	saveTestEntry(t, store, &mailEntry {ID: "entry", Owner: "bob", Mailbox: inboxMailbox, EmailID: message.ID, Date: testSent, Labels: []string {"work"}})
+
+	if entry := mustEntries(t, store, "bob", inboxMailbox)[0]; len(entry.Labels) != 1 || entry.Labels[0] != "work" {
+		t.Errorf("entry labels = %v, want [work]", entry.Labels)
+	}
+}
+
+func testStoreAttachments(t *testing.T, store AccountStore) {
+	createTestAccounts(t, store, "alice")
+	data := []byte("attached")
+
+	if err := store.SaveAttachment("file", data); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := store.AttachmentData("file"); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("AttachmentData() = %q, %v, want %q", got, err, data)
+	}
+
+	if err := store.DeleteAttachment("file"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := store.AttachmentData("file"); got != nil || err != nil {
+		t.Errorf("AttachmentData() = %q, %v after deleting it, want nil, nil", got, err)
+	}
+
+	if err := store.AddStorageUsed("alice", 5); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		size int64
+		reserved bool
+		used int64
+	}{{10, true, 15}, {10, false, 15}, {5, true, 20}} {
+		reserved, err := store.ReserveStorage("alice", test.size, 20)
+
+		if err != nil || reserved != test.reserved {
+			t.Errorf("ReserveStorage(%v) = %v, %v, want %v", test.size, reserved, err, test.reserved)
+		}
+
+		if used := mustAccount(t, store, "alice").StorageUsed; used != test.used {
+			t.Errorf("StorageUsed = %v after reserving %v, want %v", used, test.size, test.used)
+		}
+	}
+
+	if err := store.AddStorageUsed("alice", -20); err != nil {
+		t.Fatal(err)
+	}
+
+	if used := mustAccount(t, store, "alice").StorageUsed; used != 0 {
+		t.Errorf("StorageUsed = %v after freeing it all, want 0", used)
+	}
+}
+
+func testStoreSavedSearches(t *testing.T, store AccountStore) {
+	createTestAccounts(t, store, "alice")
+
+	if err := store.SetSavedSearch("alice", "support", &savedSearch {Query: "from:bob"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SetSavedSearch("alice", "support", &savedSearch {Query: "from:bob is:unread", Pinned: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if saved := mustAccount(t, store, "alice").SavedSearches; len(saved) != 1 || saved["support"].Query != "from:bob is:unread" || !saved["support"].Pinned {
+		t.Errorf("SavedSearches = %v, want the updated search", saved)
+	}
+
+	if err := store.SetSavedSearch("alice", "support", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if saved := mustAccount(t, store, "alice").SavedSearches; len(saved) != 0 {
+		t.Errorf("SavedSearches = %v after deleting it, want none", saved)
+	}
+}
+
+func testStoreTOTP(t *testing.T, store AccountStore) {
+	createTestAccounts(t, store, "alice")
+	// Stores only match the hashes, so any string stands in for one.
+	codes := []string {"first", "second"}
+
+	if err := store.SetTOTP("alice", totp {Active: true, Secret: testTOTPSecret, RecoveryCodes: codes}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		code string
+		used bool
+	}{{"first", true}, {"first", false}, {"wrong", false}} {
+		used, err := store.UseRecoveryCode("alice", test.code)
+
+		if err != nil || used != test.used {
+			t.Errorf("UseRecoveryCode(%q) = %v, %v, want %v", test.code, used, err, test.used)
+		}
+	}
+
+	for _, test := range []struct {
+		step int64
+		used bool
+	}{{2, true}, {2, false}, {1, false}} {
+		used, err := store.UseTOTPStep("alice", test.step)
+
+		if err != nil || used != test.used {
+			t.Errorf("UseTOTPStep(%v) = %v, %v, want %v", test.step, used, err, test.used)
+		}
+	}
+
+	got := mustAccount(t, store, "alice").TOTP
+
+	if !got.Active || got.Secret != testTOTPSecret || got.LastStep != 2 || len(got.RecoveryCodes) != 1 || got.RecoveryCodes[0] != codes[1] {
+		t.Errorf("TOTP = %+v, want one recovery code left and step 2 used", got)
+	}
+
+	if err := store.SetTOTP("alice", totp {RecoveryCodes: []string {}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got = mustAccount(t, store, "alice").TOTP; got.Active || len(got.RecoveryCodes) != 0 {
+		t.Errorf("TOTP = %+v after turning it off, want it cleared", got)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+// Imports
+import (
+	"fmt"
+	"time"
+	"strings"
+	"net/url"
+	"crypto/rand"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/base32"
+	"encoding/binary"
+
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Types
+type (
+	// LastStep is the newest time step a code was used for, so the same
+	// code can't be used twice while it's still in the window.
+	totp struct {
+		Active bool
+		Secret string
+		RecoveryCodes []string
+		LastStep int64
+	}
+)
+
+// Variables
+var (
+	totpPeriod = int64(30)
+	totpWindow = int64(1)
+	totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// TOTP Functions
+func createTOTPSecret() (string, error) {
+	secret := make([]byte, 20)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+func createTOTPCode(secret string, counter int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+
+	if err != nil {
+		return "", err
+	}
+
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum) - 1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset + 4]) & 0x7fffffff
+
+	return fmt.Sprintf("%06d", code % 1000000), nil
+}
+
+// checkTOTPCode returns the time step a code matches within the window,
+// skipping any step at or before the last one used.
+func checkTOTPCode(secret string, code string, lastStep int64, now time.Time) (int64, bool) {
+	counter := now.Unix() / totpPeriod
+
+	for step := counter - totpWindow; step <= counter + totpWindow; step++ {
+		if step <= lastStep {
+			continue
+		}
+
+		expected, err := createTOTPCode(secret, step)
+
+		if err == nil && hmac.Equal([]byte(expected), []byte(strings.TrimSpace(code))) {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+func createTOTPURI(username string, secret string) string {
+	return fmt.Sprintf(
+		"otpauth://totp/%v?%v",
+		url.PathEscape("Etsuko:@" + username),
+		url.Values {
+			"secret": {secret},
+			"issuer": {"Etsuko"},
+			"period": {fmt.Sprint(totpPeriod)},
+			"digits": {"6"},
+		}.Encode(),
+	)
+}
+
+func createTOTPQRCode(uri string) ([]byte, error) {
+	return qrcode.Encode(uri, qrcode.Medium, 256)
+}
+
+func createRecoveryCodes(amount int) ([]string, error) {
+	codes := []string {}
+
+	for i := 0; i < amount; i++ {
+		code := make([]byte, 5)
+
+		if _, err := rand.Read(code); err != nil {
+			return nil, err
+		}
+
+		encoded := hex.EncodeToString(code)
+		codes = append(codes, encoded[:5] + "-" + encoded[5:])
+	}
+
+	return codes, nil
+}
+
+func cleanseRecoveryCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+// Recovery codes are hashed like passwords, since they log in just the same.
+func hashRecoveryCode(code string) (string, error) {
+	return hashPassword(cleanseRecoveryCode(code))
+}
+
+// matchRecoveryCode returns the stored hash the code matches, so that one
+// can be used up, or an empty string when none do.
+func matchRecoveryCode(stored []string, code string) string {
+	code = cleanseRecoveryCode(code)
+
+	for _, hash := range stored {
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			// Codes made before bcrypt are still unsalted SHA-256 hashes.
+			sum := sha256.Sum256([]byte(code))
+
+			if subtle.ConstantTimeCompare([]byte(hash), []byte(hex.EncodeToString(sum[:]))) == 1 {
+				return hash
+			}
+		} else if bcrypt.CompareHashAndPassword([]byte(hash), []byte(code)) == nil {
+			return hash
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,151 @@
+package main
+
+// Imports
+import (
+	"time"
+	"testing"
+)
+
+// Variables
+var (
+	// The secret from RFC 6238's test vectors, its code at 59 seconds is
+	// 287082 which is time step 1.
+	testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	testTOTPCode = "287082"
+)
+
+// Test Functions
+func TestCreateTOTPCode(t *testing.T) {
+	code, err := createTOTPCode(testTOTPSecret, 1)
+
+	if err != nil || code != testTOTPCode {
+		t.Fatalf("createTOTPCode() = %q, %v, want %q", code, err, testTOTPCode)
+	}
+}
+
+func TestCheckTOTPCodeWindow(t *testing.T) {
+	tests := []struct {
+		name string
+		now int64
+		lastStep int64
+		step int64
+		valid bool
+	}{
+		{"same step", 59, 0, 1, true},
+		{"one step early", 89, 0, 1, true},
+		{"one step late", 29, 0, 1, true},
+		{"two steps early", 119, 0, 0, false},
+		{"two steps late", -30, -5, 0, false},
+		{"already used", 59, 1, 0, false},
+		{"newer step used", 89, 2, 0, false},
+		{"older step used", 59, 0, 1, true},
+	}
+
+	for _, test := range tests {
+		step, valid := checkTOTPCode(testTOTPSecret, testTOTPCode, test.lastStep, time.Unix(test.now, 0))
+
+		if step != test.step || valid != test.valid {
+			t.Errorf("%s: checkTOTPCode() = %v, %v, want %v, %v", test.name, step, valid, test.step, test.valid)
+		}
+	}
+}
+
+func TestCheckTOTPCodeReplay(t *testing.T) {
+	now := time.Unix(59, 0)
+	step, valid := checkTOTPCode(testTOTPSecret, testTOTPCode, 0, now)
+
+	if !valid {
+		t.Fatal("checkTOTPCode() rejected a fresh code")
+	}
+
+	for _, later := range []time.Time {now, now.Add(time.Second * 30)} {
+		if _, valid = checkTOTPCode(testTOTPSecret, testTOTPCode, step, later); valid {
+			t.Errorf("checkTOTPCode() accepted a used code at %v", later.Unix())
+		}
+	}
+}
+
+func TestUseTOTPStep(t *testing.T) {
+	store := newMemoryStore()
+	user := newAccount("alice", "1")
+
+	if err := store.CreateAccount(user); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		step int64
+		used bool
+	}{{2, true}, {2, false}, {1, false}, {3, true}} {
+		used, err := store.UseTOTPStep("alice", test.step)
+
+		if err != nil || used != test.used {
+			t.Errorf("UseTOTPStep(%v) = %v, %v, want %v", test.step, used, err, test.used)
+		}
+	}
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	store := newMemoryStore()
+	user := newAccount("alice", "1")
+
+	for _, code := range []string {"first", "second"} {
+		hash, err := hashRecoveryCode(code)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		user.TOTP.RecoveryCodes = append(user.TOTP.RecoveryCodes, hash)
+	}
+
+	if err := store.CreateAccount(user); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		code string
+		used bool
+	}{{" FIRST ", true}, {"first", false}, {"wrong", false}, {"second", true}} {
+		used := false
+		hash := matchRecoveryCode(mustAccount(t, store, "alice").TOTP.RecoveryCodes, test.code)
+		var err error
+
+		if hash != "" {
+			used, err = store.UseRecoveryCode("alice", hash)
+		}
+
+		if err != nil || used != test.used {
+			t.Errorf("UseRecoveryCode(%q) = %v, %v, want %v", test.code, used, err, test.used)
+		}
+	}
+}
+
+func TestHashRecoveryCode(t *testing.T) {
+	first, err := hashRecoveryCode("abcde-12345")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := hashRecoveryCode("abcde-12345")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first == second {
+		t.Error("hashRecoveryCode() gave the same hash twice, want it salted")
+	}
+
+	// Codes hashed before bcrypt, as plain SHA-256, still log in.
+	legacy := "d6d408ba357a235e081ef762cb38cb3ff4812962624ea1d63113d13c6bf8733a"
+
+	if hash := matchRecoveryCode([]string {first, legacy}, "abcde-12345"); hash != first {
+		t.Errorf("matchRecoveryCode() = %q, want the bcrypt hash", hash)
+	}
+
+	if hash := matchRecoveryCode([]string {legacy}, "ABCDE-12345"); hash != legacy {
+		t.Errorf("matchRecoveryCode() = %q, want the legacy hash", hash)
+	}
+}
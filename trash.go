@@ -0,0 +1,79 @@
+package main
+
+// Imports
+import (
+	"os"
+	"time"
+	"strconv"
+)
+
+// Trash Functions
+func trashRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("TrashDays"))
+
+	if err != nil || days <= 0 {
+		days = 30
+	}
+
+	return time.Duration(days) * time.Hour * 24
+}
+
+// trashEntries moves entries into the trash, dated for when they were
+// trashed so the purge knows how long they've been there.
+func trashEntries(entries []*mailEntry) error {
+	now := time.Now()
+
+	for _, entry := range entries {
+		entry.TrashedFrom = entry.Mailbox
+		entry.Mailbox = trashMailbox
+		entry.Date = now
+
+		if err := accounts.SaveEntry(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func restoreEntries(entries []*mailEntry) error {
+	for _, entry := range entries {
+		entry.Mailbox = entry.TrashedFrom
+		entry.TrashedFrom = ""
+		entry.Date = time.Now()
+
+		// An entry whose email has gone missing still comes back, just dated
+		// for now instead of when it was sent.
+		if entry.Email != nil {
+			entry.Date = entry.Email.Sent
+		}
+
+		if entry.Mailbox == "" {
+			entry.Mailbox = inboxMailbox
+		}
+
+		if err := accounts.SaveEntry(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// purgeTrash deletes everything that has been in the trash for longer than
+// the retention period.
+func purgeTrash(now time.Time) error {
+	expired, err := accounts.DueEntries(trashMailbox, now.Add(-trashRetention()))
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range expired {
+		if err = deleteEntries(entry.Owner, []*mailEntry {entry}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}